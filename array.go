@@ -0,0 +1,130 @@
+package jsonparser
+
+// Insert adds value to the array at keys[:len(keys)-1], before the element the last key addresses,
+// shifting that element and the ones after it. The index may be the length of the array, to add at
+// the end, and Append and Prepend may be used as well. Unlike Set, the array must exist and the index
+// must not be past its end. data is never modified.
+func Insert(data []byte, value []byte, keys ...string) ([]byte, error) {
+	return defaultParser.insert(data, value, keys...)
+}
+
+// insert implements Insert.
+func (p *Parser) insert(data []byte, value []byte, keys ...string) ([]byte, error) {
+	if len(keys) == 0 {
+		return nil, KeyPathNotFoundError
+	}
+
+	last := keys[len(keys)-1]
+	isIndex, idx := isValidArrayIndex(last)
+	if !isIndex {
+		return nil, KeyPathNotFoundError
+	}
+
+	start, elements, err := p.arraySpans(data, keys[:len(keys)-1]...)
+	if err != nil {
+		return nil, err
+	}
+	if last == Append() {
+		idx = len(elements)
+	}
+
+	// Insert before the element currently at idx, or after the last one when idx is the length of the array
+	switch {
+	case idx < len(elements):
+		first := elements[idx].offset
+		return replaceSpan(data, first, first, value, []byte{','}), nil
+	case idx > len(elements):
+		return nil, KeyPathNotFoundError
+	case idx == 0:
+		return replaceSpan(data, start+1, start+1, value), nil
+	default:
+		end := elements[idx-1].endOffset
+		return replaceSpan(data, end, end, []byte{','}, value), nil
+	}
+}
+
+// ArrayRemove removes the elements from index from up to, but not including, index to of the array at keys,
+// shifting the later elements down. from must not be greater than to, nor to than the length of the array.
+// data is never modified.
+func ArrayRemove(data []byte, from, to int, keys ...string) ([]byte, error) {
+	return defaultParser.arrayRemove(data, from, to, keys...)
+}
+
+// arrayRemove implements ArrayRemove.
+func (p *Parser) arrayRemove(data []byte, from, to int, keys ...string) ([]byte, error) {
+	start, elements, err := p.arraySpans(data, keys...)
+	if err != nil {
+		return nil, err
+	}
+	if from < 0 || from > to || to > len(elements) {
+		return nil, KeyPathNotFoundError
+	}
+
+	switch {
+	case from == to:
+		return data, nil
+	case to < len(elements):
+		// Take the commas after the removed elements
+		return replaceSpan(data, elements[from].offset, elements[to].offset), nil
+	case from > 0:
+		// Take the commas before them
+		return replaceSpan(data, elements[from-1].endOffset, elements[to-1].endOffset), nil
+	default:
+		return replaceSpan(data, start+1, elements[to-1].endOffset), nil
+	}
+}
+
+// ArrayAppend adds values to the end of the array at keys, in order, creating the array and any objects
+// holding it like Set does when they are missing. A value at keys that is not an array returns KeyPathNotFoundError. data is never modified.
+func ArrayAppend(data []byte, keys []string, values ...[]byte) ([]byte, error) {
+	return defaultParser.arrayAppend(data, keys, values...)
+}
+
+// arrayAppend implements ArrayAppend.
+func (p *Parser) arrayAppend(data []byte, keys []string, values ...[]byte) ([]byte, error) {
+	if len(values) == 0 {
+		return data, nil
+	}
+
+	list := make([]byte, 0, len(values)*8)
+	for i, v := range values {
+		if i > 0 {
+			list = append(list, ',')
+		}
+		list = append(list, v...)
+	}
+
+	_, dataType, start, _, err := p.internalGet(data, keys...)
+	if err == KeyPathNotFoundError && len(keys) > 0 {
+		return p.appendSet(nil, data, append(append([]byte{'['}, list...), ']'), keys...)
+	} else if err != nil {
+		return nil, err
+	} else if dataType != Array {
+		return nil, KeyPathNotFoundError
+	}
+
+	elements, err := containerSpans(data, start)
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) == 0 {
+		return replaceSpan(data, start+1, start+1, list), nil
+	}
+	end := elements[len(elements)-1].endOffset
+	return replaceSpan(data, end, end, []byte{','}, list), nil
+}
+
+// arraySpans returns where the array at keys starts and the spans of its elements.
+// It returns KeyPathNotFoundError if the value at keys is not an array.
+func (p *Parser) arraySpans(data []byte, keys ...string) (int, []editSpan, error) {
+	_, dataType, start, _, err := p.internalGet(data, keys...)
+	if err != nil {
+		return -1, nil, err
+	}
+	if dataType != Array {
+		return -1, nil, KeyPathNotFoundError
+	}
+
+	elements, err := containerSpans(data, start)
+	return start, elements, err
+}
@@ -0,0 +1,112 @@
+package jsonparser
+
+import (
+	"testing"
+)
+
+var insertTests = []struct {
+	desc     string
+	json     string
+	path     []string
+	expected string
+	err      error
+}{
+	{desc: "middle", json: `{"a":[1,2,3]}`, path: []string{"a", "[1]"}, expected: `{"a":[1,0,2,3]}`},
+	{desc: "start", json: `{"a":[1, 2]}`, path: []string{"a", "[0]"}, expected: `{"a":[0,1, 2]}`},
+	{desc: "end", json: `{"a":[1, 2]}`, path: []string{"a", "[2]"}, expected: `{"a":[1, 2,0]}`},
+	{desc: "append", json: `{"a":[1]}`, path: []string{"a", "[+]"}, expected: `{"a":[1,0]}`},
+	{desc: "prepend", json: `{"a":[1]}`, path: []string{"a", "[-]"}, expected: `{"a":[0,1]}`},
+	{desc: "empty array", json: `{"a":[ ]}`, path: []string{"a", "[0]"}, expected: `{"a":[0 ]}`},
+	{desc: "top-level array", json: `[[1],[2]]`, path: []string{"[1]", "[1]"}, expected: `[[1],[2,0]]`},
+	{desc: "past the end", json: `{"a":[1]}`, path: []string{"a", "[2]"}, err: KeyPathNotFoundError},
+	{desc: "not an array", json: `{"a":{}}`, path: []string{"a", "[0]"}, err: KeyPathNotFoundError},
+	{desc: "missing array", json: `{}`, path: []string{"a", "[0]"}, err: KeyPathNotFoundError},
+	{desc: "key instead of index", json: `{"a":[1]}`, path: []string{"a", "b"}, err: KeyPathNotFoundError},
+}
+
+func TestInsert(t *testing.T) {
+	for _, test := range insertTests {
+		data := []byte(test.json)
+		value, err := Insert(data, []byte(`0`), test.path...)
+
+		if err != test.err {
+			t.Errorf("Insert test '%s' expected error %v, obtained %v", test.desc, test.err, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("Insert test '%s' expected %s, obtained %s", test.desc, test.expected, value)
+		}
+		if string(data) != test.json {
+			t.Errorf("Insert test '%s' modified its input: %s", test.desc, data)
+		}
+	}
+}
+
+var arrayRemoveTests = []struct {
+	desc     string
+	json     string
+	from, to int
+	path     []string
+	expected string
+	err      error
+}{
+	{desc: "middle", json: `{"a":[1, 2, 3, 4]}`, from: 1, to: 3, path: []string{"a"}, expected: `{"a":[1, 4]}`},
+	{desc: "start", json: `{"a":[1, 2, 3]}`, from: 0, to: 1, path: []string{"a"}, expected: `{"a":[2, 3]}`},
+	{desc: "end", json: `{"a":[1, 2, 3]}`, from: 1, to: 3, path: []string{"a"}, expected: `{"a":[1]}`},
+	{desc: "all", json: "{\"a\":[\n  1,\n  2\n]}", from: 0, to: 2, path: []string{"a"}, expected: "{\"a\":[\n]}"},
+	{desc: "nothing", json: `{"a":[1]}`, from: 1, to: 1, path: []string{"a"}, expected: `{"a":[1]}`},
+	{desc: "nested values", json: `[[1,[2]],{"b":3},4]`, from: 0, to: 2, expected: `[4]`},
+	{desc: "past the end", json: `{"a":[1]}`, from: 0, to: 2, path: []string{"a"}, err: KeyPathNotFoundError},
+	{desc: "reversed range", json: `{"a":[1,2]}`, from: 1, to: 0, path: []string{"a"}, err: KeyPathNotFoundError},
+	{desc: "not an array", json: `{"a":1}`, from: 0, to: 0, path: []string{"a"}, err: KeyPathNotFoundError},
+}
+
+func TestArrayRemove(t *testing.T) {
+	for _, test := range arrayRemoveTests {
+		data := []byte(test.json)
+		value, err := ArrayRemove(data, test.from, test.to, test.path...)
+
+		if err != test.err {
+			t.Errorf("ArrayRemove test '%s' expected error %v, obtained %v", test.desc, test.err, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("ArrayRemove test '%s' expected %s, obtained %s", test.desc, test.expected, value)
+		}
+		if string(data) != test.json {
+			t.Errorf("ArrayRemove test '%s' modified its input: %s", test.desc, data)
+		}
+	}
+}
+
+var arrayAppendTests = []struct {
+	desc     string
+	json     string
+	path     []string
+	values   []string
+	expected string
+	err      error
+}{
+	{desc: "several values", json: `{"a":[1]}`, path: []string{"a"}, values: []string{`2`, `"x"`, `{"b":3}`}, expected: `{"a":[1,2,"x",{"b":3}]}`},
+	{desc: "empty array", json: `{"a":[ ]}`, path: []string{"a"}, values: []string{`1`, `2`}, expected: `{"a":[1,2 ]}`},
+	{desc: "top-level array", json: `[1]`, values: []string{`2`}, expected: `[1,2]`},
+	{desc: "missing array", json: `{"a":{}}`, path: []string{"a", "b"}, values: []string{`1`, `2`}, expected: `{"a":{"b":[1,2]}}`},
+	{desc: "no values", json: `{"a":[1]}`, path: []string{"a"}, expected: `{"a":[1]}`},
+	{desc: "not an array", json: `{"a":1}`, path: []string{"a"}, values: []string{`2`}, err: KeyPathNotFoundError},
+}
+
+func TestArrayAppend(t *testing.T) {
+	for _, test := range arrayAppendTests {
+		data := []byte(test.json)
+		var values [][]byte
+		for _, v := range test.values {
+			values = append(values, []byte(v))
+		}
+		value, err := ArrayAppend(data, test.path, values...)
+
+		if err != test.err {
+			t.Errorf("ArrayAppend test '%s' expected error %v, obtained %v", test.desc, test.err, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("ArrayAppend test '%s' expected %s, obtained %s", test.desc, test.expected, value)
+		}
+		if string(data) != test.json {
+			t.Errorf("ArrayAppend test '%s' modified its input: %s", test.desc, data)
+		}
+	}
+}
@@ -1,9 +1,14 @@
 package jsonparser
 
-// About 3x faster then strconv.ParseInt because does not check for range error and support only base 10, which is enough for JSON
-func parseInt(bytes []byte) (v int64, ok bool) {
+import (
+	"math"
+)
+
+// About 3x faster then strconv.ParseInt because it supports only base 10, which is enough for JSON.
+// ok is false if the input is malformed or out of range; overflow tells the two cases apart.
+func parseInt(bytes []byte) (v int64, ok bool, overflow bool) {
 	if len(bytes) == 0 {
-		return 0, false
+		return 0, false, false
 	}
 
 	var neg bool = false
@@ -12,17 +17,46 @@ func parseInt(bytes []byte) (v int64, ok bool) {
 		bytes = bytes[1:]
 	}
 
-	for _, c := range bytes {
-		if c >= '0' && c <= '9' {
-			v = (10 * v) + int64(c-'0')
-		} else {
-			return 0, false
-		}
+	u, ok, overflow := parseUint(bytes)
+	if !ok {
+		return 0, false, overflow
 	}
 
 	if neg {
-		return -v, true
+		// The magnitude of math.MinInt64 is one more than that of math.MaxInt64
+		if u > -math.MinInt64 {
+			return 0, false, true
+		}
+		return -int64(u), true, false
 	} else {
-		return v, true
+		if u > math.MaxInt64 {
+			return 0, false, true
+		}
+		return int64(u), true, false
+	}
+}
+
+// parseUint is the unsigned counterpart of parseInt, and does not accept a sign.
+func parseUint(bytes []byte) (v uint64, ok bool, overflow bool) {
+	if len(bytes) == 0 {
+		return 0, false, false
+	}
+
+	for _, c := range bytes {
+		if c < '0' || c > '9' {
+			return 0, false, false
+		}
+
+		// Keep scanning after an overflow so malformed input is still reported as such
+		d := uint64(c - '0')
+		if v > (math.MaxUint64-d)/10 {
+			overflow = true
+		}
+		v = (10 * v) + d
+	}
+
+	if overflow {
+		return 0, false, true
 	}
+	return v, true, false
 }
@@ -7,9 +7,10 @@ import (
 )
 
 type ParseIntTest struct {
-	in    string
-	out   int64
-	isErr bool
+	in         string
+	out        int64
+	isErr      bool
+	isOverflow bool
 }
 
 var parseIntTests = []ParseIntTest{
@@ -42,8 +43,24 @@ var parseIntTests = []ParseIntTest{
 		out: -9223372036854775808,
 	},
 	{
-		in:  "18446744073709551616", // = 2^64; integer overflow is not detected
-		out: 0,
+		in:         "9223372036854775808", // = 2^63
+		isErr:      true,
+		isOverflow: true,
+	},
+	{
+		in:         "-9223372036854775809",
+		isErr:      true,
+		isOverflow: true,
+	},
+	{
+		in:         "18446744073709551616", // = 2^64
+		isErr:      true,
+		isOverflow: true,
+	},
+	{
+		in:         "99999999999999999999",
+		isErr:      true,
+		isOverflow: true,
 	},
 
 	{
@@ -66,13 +83,80 @@ var parseIntTests = []ParseIntTest{
 		in:    "9223372036854775807x",
 		isErr: true,
 	},
+	{
+		in:    "99999999999999999999x", // malformed input is not reported as overflow
+		isErr: true,
+	},
+	{
+		in:    "-",
+		isErr: true,
+	},
 }
 
 func TestBytesParseInt(t *testing.T) {
 	for _, test := range parseIntTests {
-		out, ok := parseInt([]byte(test.in))
+		out, ok, overflow := parseInt([]byte(test.in))
+		if ok != !test.isErr {
+			t.Errorf("Test '%s' error return did not match expectation (obtained %t, expected %t)", test.in, !ok, test.isErr)
+		} else if overflow != test.isOverflow {
+			t.Errorf("Test '%s' overflow return did not match expectation (obtained %t, expected %t)", test.in, overflow, test.isOverflow)
+		} else if ok && out != test.out {
+			t.Errorf("Test '%s' did not return the expected value (obtained %d, expected %d)", test.in, out, test.out)
+		}
+	}
+}
+
+type ParseUintTest struct {
+	in         string
+	out        uint64
+	isErr      bool
+	isOverflow bool
+}
+
+var parseUintTests = []ParseUintTest{
+	{
+		in:  "0",
+		out: 0,
+	},
+	{
+		in:  "9223372036854775808",
+		out: 9223372036854775808,
+	},
+	{
+		in:  "18446744073709551615",
+		out: 18446744073709551615,
+	},
+	{
+		in:         "18446744073709551616",
+		isErr:      true,
+		isOverflow: true,
+	},
+	{
+		in:         "184467440737095516150",
+		isErr:      true,
+		isOverflow: true,
+	},
+	{
+		in:    "-1", // signs are handled by the callers
+		isErr: true,
+	},
+	{
+		in:    "",
+		isErr: true,
+	},
+	{
+		in:    "1.5",
+		isErr: true,
+	},
+}
+
+func TestBytesParseUint(t *testing.T) {
+	for _, test := range parseUintTests {
+		out, ok, overflow := parseUint([]byte(test.in))
 		if ok != !test.isErr {
 			t.Errorf("Test '%s' error return did not match expectation (obtained %t, expected %t)", test.in, !ok, test.isErr)
+		} else if overflow != test.isOverflow {
+			t.Errorf("Test '%s' overflow return did not match expectation (obtained %t, expected %t)", test.in, overflow, test.isOverflow)
 		} else if ok && out != test.out {
 			t.Errorf("Test '%s' did not return the expected value (obtained %d, expected %d)", test.in, out, test.out)
 		}
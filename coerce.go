@@ -0,0 +1,123 @@
+package jsonparser
+
+import (
+	"fmt"
+)
+
+// unwrapValue returns a value retrieved by `Get`, unescaping strings so their content can be read as another type.
+func unwrapValue(v []byte, t ValueType, e error) ([]byte, ValueType, error) {
+	if e != nil {
+		return nil, t, e
+	}
+
+	// Strings without escapes are returned as they are; others are unescaped into a new slice
+	if t == String {
+		if u, err := Unescape(v, nil); err != nil {
+			return nil, t, MalformedStringEscapeError
+		} else {
+			v = u
+		}
+	}
+
+	return v, t, nil
+}
+
+// isNumber reports whether b is a valid JSON number, which excludes forms such as "+1", "0x1" or "NaN" that strconv accepts.
+func isNumber(b []byte) bool {
+	end, err := validateNumber(b, 0)
+	return err == nil && end == len(b)
+}
+
+// GetIntCoerce returns the value retrieved by `Get` as an int64, accepting numbers wrapped in strings ("42")
+// and integral numbers written with a fraction or exponent (1.0, 1e3).
+// Anything else, including fractional values, will return an error.
+func GetIntCoerce(data []byte, keys ...string) (val int64, err error) {
+	v, t, _, e := Get(data, keys...)
+	return intCoerceValue(unwrapValue(v, t, e))
+}
+
+func intCoerceValue(v []byte, t ValueType, e error) (int64, error) {
+	if e != nil {
+		return 0, e
+	}
+
+	if (t == Number || t == String) && isNumber(v) {
+		if i, err := ParseInt(v); err != MalformedValueError {
+			return i, err
+		}
+		// Not a plain integer; accept it if the digits after the point are all zero
+		if i, err := ParseDecimal(v, 0); err != DecimalPrecisionError {
+			return i, err
+		}
+	}
+
+	return 0, fmt.Errorf("Value can't be coerced to an integer: %s", string(v))
+}
+
+// GetFloatCoerce returns the value retrieved by `Get` as a float64, accepting numbers wrapped in strings ("1.5").
+// Anything else will return an error.
+func GetFloatCoerce(data []byte, keys ...string) (val float64, err error) {
+	v, t, _, e := Get(data, keys...)
+	return floatCoerceValue(unwrapValue(v, t, e))
+}
+
+func floatCoerceValue(v []byte, t ValueType, e error) (float64, error) {
+	if e != nil {
+		return 0, e
+	}
+
+	if (t == Number || t == String) && isNumber(v) {
+		return ParseFloat(v)
+	}
+
+	return 0, fmt.Errorf("Value can't be coerced to a number: %s", string(v))
+}
+
+// GetBoolCoerce returns the value retrieved by `Get` as a bool, accepting the numbers 0 and 1 as well as
+// "true", "false", "1" and "0" wrapped in strings. Anything else will return an error.
+func GetBoolCoerce(data []byte, keys ...string) (val bool, err error) {
+	v, t, _, e := Get(data, keys...)
+	return boolCoerceValue(unwrapValue(v, t, e))
+}
+
+func boolCoerceValue(v []byte, t ValueType, e error) (bool, error) {
+	if e != nil {
+		return false, e
+	}
+
+	switch t {
+	case Boolean, String:
+		if b, err := ParseBoolean(v); err == nil {
+			return b, nil
+		}
+	}
+
+	switch t {
+	case Number, String:
+		if i, err := ParseInt(v); err == nil && (i == 0 || i == 1) {
+			return i == 1, nil
+		}
+	}
+
+	return false, fmt.Errorf("Value can't be coerced to a boolean: %s", string(v))
+}
+
+// GetStringCoerce returns the value retrieved by `Get` as a string, unescaping strings like `GetString` and
+// returning numbers and booleans as their literal text. Objects, arrays and null will return an error.
+func GetStringCoerce(data []byte, keys ...string) (val string, err error) {
+	v, t, _, e := Get(data, keys...)
+	return stringCoerceValue(unwrapValue(v, t, e))
+}
+
+func stringCoerceValue(v []byte, t ValueType, e error) (string, error) {
+	if e != nil {
+		return "", e
+	}
+
+	switch t {
+	case String, Number, Boolean:
+		return string(v), nil
+	}
+
+	return "", fmt.Errorf("Value can't be coerced to a string: %s", string(v))
+}
@@ -0,0 +1,118 @@
+package jsonparser
+
+import (
+	"testing"
+)
+
+var coerceJson = `{
+	"int": 42, "intStr": "42", "negStr": "-7", "float": 1.0, "exp": 1e3, "expStr": "2.5e1",
+	"frac": 1.5, "fracStr": "1.5", "big": 99999999999999999999, "word": "forty-two", "escaped": "\u0034\u0032",
+	"plus": "+1", "nan": "NaN", "padded": " 42 ", "empty": "",
+	"true": true, "false": false, "trueStr": "true", "falseStr": "false", "one": 1, "zero": 0, "oneStr": "1", "two": 2,
+	"null": null, "obj": {"a": 1}, "arr": [1]
+}`
+
+var getIntCoerceTests = []GetTest{
+	{desc: "number", json: coerceJson, path: []string{"int"}, isFound: true, data: int64(42)},
+	{desc: "string-wrapped number", json: coerceJson, path: []string{"intStr"}, isFound: true, data: int64(42)},
+	{desc: "negative string-wrapped number", json: coerceJson, path: []string{"negStr"}, isFound: true, data: int64(-7)},
+	{desc: "integral float", json: coerceJson, path: []string{"float"}, isFound: true, data: int64(1)},
+	{desc: "integral exponent", json: coerceJson, path: []string{"exp"}, isFound: true, data: int64(1000)},
+	{desc: "string-wrapped integral exponent", json: coerceJson, path: []string{"expStr"}, isFound: true, data: int64(25)},
+	{desc: "escaped digits", json: coerceJson, path: []string{"escaped"}, isFound: true, data: int64(42)},
+	{desc: "fraction", json: coerceJson, path: []string{"frac"}, isErr: true},
+	{desc: "string-wrapped fraction", json: coerceJson, path: []string{"fracStr"}, isErr: true},
+	{desc: "overflow", json: coerceJson, path: []string{"big"}, isErr: true},
+	{desc: "word", json: coerceJson, path: []string{"word"}, isErr: true},
+	{desc: "plus sign", json: coerceJson, path: []string{"plus"}, isErr: true},
+	{desc: "padded", json: coerceJson, path: []string{"padded"}, isErr: true},
+	{desc: "empty string", json: coerceJson, path: []string{"empty"}, isErr: true},
+	{desc: "boolean", json: coerceJson, path: []string{"true"}, isErr: true},
+	{desc: "null", json: coerceJson, path: []string{"null"}, isErr: true},
+	{desc: "missing", json: coerceJson, path: []string{"missing"}, isFound: false},
+}
+
+var getFloatCoerceTests = []GetTest{
+	{desc: "number", json: coerceJson, path: []string{"frac"}, isFound: true, data: float64(1.5)},
+	{desc: "string-wrapped number", json: coerceJson, path: []string{"fracStr"}, isFound: true, data: float64(1.5)},
+	{desc: "string-wrapped exponent", json: coerceJson, path: []string{"expStr"}, isFound: true, data: float64(25)},
+	{desc: "NaN", json: coerceJson, path: []string{"nan"}, isErr: true},
+	{desc: "word", json: coerceJson, path: []string{"word"}, isErr: true},
+	{desc: "object", json: coerceJson, path: []string{"obj"}, isErr: true},
+}
+
+var getBoolCoerceTests = []GetTest{
+	{desc: "true", json: coerceJson, path: []string{"true"}, isFound: true, data: true},
+	{desc: "false", json: coerceJson, path: []string{"false"}, isFound: true, data: false},
+	{desc: "string-wrapped true", json: coerceJson, path: []string{"trueStr"}, isFound: true, data: true},
+	{desc: "string-wrapped false", json: coerceJson, path: []string{"falseStr"}, isFound: true, data: false},
+	{desc: "one", json: coerceJson, path: []string{"one"}, isFound: true, data: true},
+	{desc: "zero", json: coerceJson, path: []string{"zero"}, isFound: true, data: false},
+	{desc: "string-wrapped one", json: coerceJson, path: []string{"oneStr"}, isFound: true, data: true},
+	{desc: "two", json: coerceJson, path: []string{"two"}, isErr: true},
+	{desc: "fraction", json: coerceJson, path: []string{"frac"}, isErr: true},
+	{desc: "word", json: coerceJson, path: []string{"word"}, isErr: true},
+	{desc: "null", json: coerceJson, path: []string{"null"}, isErr: true},
+}
+
+var getStringCoerceTests = []GetTest{
+	{desc: "string", json: coerceJson, path: []string{"word"}, isFound: true, data: "forty-two"},
+	{desc: "escaped string", json: coerceJson, path: []string{"escaped"}, isFound: true, data: "42"},
+	{desc: "number", json: coerceJson, path: []string{"exp"}, isFound: true, data: "1e3"},
+	{desc: "boolean", json: coerceJson, path: []string{"false"}, isFound: true, data: "false"},
+	{desc: "null", json: coerceJson, path: []string{"null"}, isErr: true},
+	{desc: "object", json: coerceJson, path: []string{"obj"}, isErr: true},
+	{desc: "array", json: coerceJson, path: []string{"arr"}, isErr: true},
+}
+
+func TestGetIntCoerce(t *testing.T) {
+	runGetTests(t, "GetIntCoerce()", getIntCoerceTests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetIntCoerce([]byte(test.json), test.path...)
+			return value, Number, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(int64)
+			return expected == value.(int64), expected
+		},
+	)
+}
+
+func TestGetFloatCoerce(t *testing.T) {
+	runGetTests(t, "GetFloatCoerce()", getFloatCoerceTests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetFloatCoerce([]byte(test.json), test.path...)
+			return value, Number, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(float64)
+			return expected == value.(float64), expected
+		},
+	)
+}
+
+func TestGetBoolCoerce(t *testing.T) {
+	runGetTests(t, "GetBoolCoerce()", getBoolCoerceTests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetBoolCoerce([]byte(test.json), test.path...)
+			return value, Boolean, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(bool)
+			return expected == value.(bool), expected
+		},
+	)
+}
+
+func TestGetStringCoerce(t *testing.T) {
+	runGetTests(t, "GetStringCoerce()", getStringCoerceTests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetStringCoerce([]byte(test.json), test.path...)
+			return value, String, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(string)
+			return expected == value.(string), expected
+		},
+	)
+}
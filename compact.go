@@ -0,0 +1,12 @@
+package jsonparser
+
+// Compact writes src without insignificant whitespace into dst[:0], growing it only if it is too small,
+// and returns the result. It reads src once, validating it as it goes like encoding/json's Compact,
+// and returns an error for anything that is not valid JSON.
+//
+// Compact does not allocate when dst has the capacity for the result, and objects and arrays are nested
+// fewer than 32 levels deep. The output is never longer than the input read so far, so dst may be src[:0]
+// to compact src in place.
+func Compact(dst, src []byte) ([]byte, error) {
+	return walkValue(dst[:0], src, true)
+}
@@ -0,0 +1,83 @@
+package jsonparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var compactTests = []struct {
+	desc     string
+	json     string
+	expected string
+	isErr    bool
+}{
+	{desc: "pretty object", json: "{\n  \"a\": 1,\n  \"b\": [\n    true,\n    null\n  ]\n}\n", expected: `{"a":1,"b":[true,null]}`},
+	{desc: "whitespace inside strings is kept", json: `{ "a b" : " c\" d " }`, expected: `{"a b":" c\" d "}`},
+	{desc: "escapes are kept", json: `[ "\u0041\n" ]`, expected: `["\u0041\n"]`},
+	{desc: "scalar", json: " -1.5e3 ", expected: `-1.5e3`},
+	{desc: "empty containers", json: "[ { } , [ ] ]", expected: `[{},[]]`},
+	{desc: "already compact", json: `{"a":[1,2,{"b":"c"}]}`, expected: `{"a":[1,2,{"b":"c"}]}`},
+
+	{desc: "empty", json: ``, isErr: true},
+	{desc: "unclosed object", json: `{"a":1 `, isErr: true},
+	{desc: "mismatched brackets", json: `[1}`, isErr: true},
+	{desc: "trailing comma", json: `[1, ]`, isErr: true},
+	{desc: "missing comma", json: `{"a":1 "b":2}`, isErr: true},
+	{desc: "missing colon", json: `{"a" 1}`, isErr: true},
+	{desc: "unquoted key", json: `{a:1}`, isErr: true},
+	{desc: "unterminated string", json: `["abc]`, isErr: true},
+	{desc: "invalid escape", json: `["a\x"]`, isErr: true},
+	{desc: "control character in key", json: "{\"a\tb\":1}", isErr: true},
+	{desc: "bad number", json: `[01]`, isErr: true},
+	{desc: "bad literal", json: `[nul]`, isErr: true},
+	{desc: "trailing garbage", json: `{} x`, isErr: true},
+}
+
+func TestCompact(t *testing.T) {
+	for _, test := range compactTests {
+		value, err := Compact(nil, []byte(test.json))
+		if (err != nil) != test.isErr {
+			t.Errorf("Compact test '%s' isErr mismatch: expected %t, obtained %v", test.desc, test.isErr, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("Compact test '%s' expected %s, obtained %s", test.desc, test.expected, value)
+		}
+	}
+}
+
+func TestCompactMatchesEncodingJSON(t *testing.T) {
+	for _, test := range validateTests {
+		if test.isErr {
+			continue
+		}
+
+		var expected bytes.Buffer
+		if err := json.Compact(&expected, []byte(test.json)); err != nil {
+			t.Fatalf("json.Compact failed on '%s': %v", test.desc, err)
+		}
+		if value, err := Compact(nil, []byte(test.json)); err != nil || !bytes.Equal(value, expected.Bytes()) {
+			t.Errorf("Compact test '%s' returned %s, %v; expected %s", test.desc, value, err, expected.Bytes())
+		}
+	}
+}
+
+func TestCompactInPlace(t *testing.T) {
+	src := []byte("{\n  \"a\": [ 1, 2 ],\n  \"b\": \"c d\"\n}")
+	value, err := Compact(src[:0], src)
+	if err != nil || string(value) != `{"a":[1,2],"b":"c d"}` {
+		t.Errorf("Compact in place returned %s, %v", value, err)
+	}
+}
+
+func TestCompactAllocations(t *testing.T) {
+	src := []byte("{\n  \"a\": [ 1, 2, {\"b\": null} ],\n  \"c\": \"d\"\n}")
+	dst := make([]byte, 0, len(src))
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := Compact(dst, src); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Compact into a large enough buffer allocated %v times", allocs)
+	}
+}
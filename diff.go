@@ -0,0 +1,285 @@
+package jsonparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DiffOption configures Diff.
+type DiffOption func(*differ)
+
+// DiffArraysByKey makes Diff match array elements by the value of their member key, such as "id",
+// rather than by position. Elements that moved become move operations, and changes inside them are
+// reported under their new index. Arrays where an element is not an object with a string or number
+// key, or where keys repeat, are still compared by position.
+func DiffArraysByKey(key string) DiffOption {
+	return func(d *differ) {
+		d.arrayKey = key
+	}
+}
+
+// Diff returns a JSON Patch (RFC 6902) that turns a into b when passed to ApplyPatch.
+// Objects are compared member by member regardless of order, and values that differ in type are
+// replaced as a whole. Equal documents give an empty patch, "[]".
+func Diff(a, b []byte, opts ...DiffOption) ([]byte, error) {
+	d := &differ{out: []byte{'['}}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	_, _, aStart, aEnd, err := internalGet(a)
+	if err != nil {
+		return nil, err
+	}
+	_, _, bStart, bEnd, err := internalGet(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.diff("", a[aStart:aEnd], b[bStart:bEnd]); err != nil {
+		return nil, err
+	}
+	return append(d.out, ']'), nil
+}
+
+// differ accumulates the operations of a patch.
+type differ struct {
+	out      []byte
+	arrayKey string
+}
+
+// diff appends the operations turning a into b. Objects and arrays are walked once, and emit nothing
+// when nothing in them changed; only scalars and values of different types are compared whole.
+func (d *differ) diff(path string, a, b []byte) error {
+	switch {
+	case a[0] == '{' && b[0] == '{':
+		return d.diffObjects(path, a, b)
+	case a[0] == '[' && b[0] == '[':
+		return d.diffArrays(path, a, b)
+	}
+
+	if !equalValues(a, b) {
+		d.op("replace", path, "", b)
+	}
+	return nil
+}
+
+func (d *differ) diffObjects(path string, a, b []byte) error {
+	aSpans, err := containerSpans(a, 0)
+	if err != nil {
+		return err
+	}
+	bSpans, err := containerSpans(b, 0)
+	if err != nil {
+		return err
+	}
+
+	// Repeated keys are read like encoding/json reads them, the last one winning
+	aValues := make(map[string][]byte, len(aSpans))
+	for _, s := range aSpans {
+		aValues[string(s.key)] = a[s.offset:s.endOffset]
+	}
+	bMembers := make(map[string][]byte, len(bSpans))
+	for _, s := range bSpans {
+		bMembers[string(s.key)] = b[s.offset:s.endOffset]
+	}
+	aMembers := make(map[string]bool, len(aSpans))
+
+	for _, s := range aSpans {
+		name := string(s.key)
+		if aMembers[name] {
+			continue
+		}
+		aMembers[name] = true
+
+		if bv, ok := bMembers[name]; !ok {
+			d.op("remove", pointerAppend(path, name), "", nil)
+		} else if err := d.diff(pointerAppend(path, name), aValues[name], bv); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range bSpans {
+		if name := string(s.key); !aMembers[name] {
+			aMembers[name] = true
+			d.op("add", pointerAppend(path, name), "", bMembers[name])
+		}
+	}
+
+	return nil
+}
+
+func (d *differ) diffArrays(path string, a, b []byte) error {
+	aSpans, err := containerSpans(a, 0)
+	if err != nil {
+		return err
+	}
+	bSpans, err := containerSpans(b, 0)
+	if err != nil {
+		return err
+	}
+
+	aElems, bElems := make([][]byte, len(aSpans)), make([][]byte, len(bSpans))
+	for i, s := range aSpans {
+		aElems[i] = a[s.offset:s.endOffset]
+	}
+	for i, s := range bSpans {
+		bElems[i] = b[s.offset:s.endOffset]
+	}
+
+	if d.arrayKey != "" {
+		if aIDs, bIDs, ok := d.elementIDs(aElems, bElems); ok {
+			return d.diffArraysByKey(path, aElems, bElems, aIDs, bIDs)
+		}
+	}
+
+	common := len(aElems)
+	if len(bElems) < common {
+		common = len(bElems)
+	}
+	for i := 0; i < common; i++ {
+		if err := d.diff(pointerAppend(path, strconv.Itoa(i)), aElems[i], bElems[i]); err != nil {
+			return err
+		}
+	}
+
+	// Remove from the end, so the indexes of the remaining elements do not change
+	for i := len(aElems) - 1; i >= common; i-- {
+		d.op("remove", pointerAppend(path, strconv.Itoa(i)), "", nil)
+	}
+	for i := common; i < len(bElems); i++ {
+		d.op("add", pointerAppend(path, "-"), "", bElems[i])
+	}
+
+	return nil
+}
+
+// diffArraysByKey diffs arrays whose elements are identified by aIDs and bIDs.
+func (d *differ) diffArraysByKey(path string, aElems, bElems [][]byte, aIDs, bIDs []string) error {
+	inB := make(map[string]bool, len(bIDs))
+	for _, id := range bIDs {
+		inB[id] = true
+	}
+
+	// Remove the elements that are gone, from the end so the indexes of the others do not change
+	var ids []string
+	var elems [][]byte
+	for i := len(aIDs) - 1; i >= 0; i-- {
+		if !inB[aIDs[i]] {
+			d.op("remove", pointerAppend(path, strconv.Itoa(i)), "", nil)
+		}
+	}
+	for i, id := range aIDs {
+		if inB[id] {
+			ids = append(ids, id)
+			elems = append(elems, aElems[i])
+		}
+	}
+
+	// Then bring the elements into the order of b, one index at a time. Elements before i are in
+	// place, so an element found further on is moved back to i.
+	for i, id := range bIDs {
+		j := i
+		for j < len(ids) && ids[j] != id {
+			j++
+		}
+
+		if j == len(ids) {
+			d.op("add", pointerAppend(path, strconv.Itoa(i)), "", bElems[i])
+			ids = append(ids[:i], append([]string{id}, ids[i:]...)...)
+			elems = append(elems[:i], append([][]byte{bElems[i]}, elems[i:]...)...)
+			continue
+		}
+
+		if j != i {
+			d.op("move", pointerAppend(path, strconv.Itoa(i)), pointerAppend(path, strconv.Itoa(j)), nil)
+			moved := elems[j]
+			copy(ids[i+1:j+1], ids[i:j])
+			copy(elems[i+1:j+1], elems[i:j])
+			ids[i], elems[i] = id, moved
+		}
+
+		if err := d.diff(pointerAppend(path, strconv.Itoa(i)), elems[i], bElems[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// elementIDs returns the identity of every element of both arrays, or false if an element has none
+// or an identity repeats within an array.
+func (d *differ) elementIDs(aElems, bElems [][]byte) (aIDs, bIDs []string, ok bool) {
+	ids := func(elems [][]byte) ([]string, bool) {
+		seen := make(map[string]bool, len(elems))
+		result := make([]string, len(elems))
+		for i, elem := range elems {
+			if elem[0] != '{' {
+				return nil, false
+			}
+			v, dt, _, err := Get(elem, Key(d.arrayKey))
+			if err != nil {
+				return nil, false
+			}
+
+			// Strings and numbers never share an identity
+			switch dt {
+			case String:
+				u, err := Unescape(v, nil)
+				if err != nil {
+					return nil, false
+				}
+				result[i] = "s" + string(u)
+			case Number:
+				result[i] = "n" + string(v)
+			default:
+				return nil, false
+			}
+
+			if seen[result[i]] {
+				return nil, false
+			}
+			seen[result[i]] = true
+		}
+		return result, true
+	}
+
+	if aIDs, ok = ids(aElems); !ok {
+		return nil, nil, false
+	}
+	if bIDs, ok = ids(bElems); !ok {
+		return nil, nil, false
+	}
+	return aIDs, bIDs, true
+}
+
+// op appends an operation to the patch. from is only written for moves, and value when not nil.
+func (d *differ) op(name, path, from string, value []byte) {
+	if len(d.out) > 1 {
+		d.out = append(d.out, ',')
+	}
+
+	d.out = append(d.out, `{"op":"`...)
+	d.out = append(d.out, name...)
+	if name == "move" {
+		d.out = append(d.out, `","from":`...)
+		d.out = AppendQuoted(d.out, from)
+		d.out = append(d.out, `,"path":`...)
+	} else {
+		d.out = append(d.out, `","path":`...)
+	}
+	d.out = AppendQuoted(d.out, path)
+	if value != nil {
+		d.out = append(d.out, `,"value":`...)
+		d.out = append(d.out, value...)
+	}
+	d.out = append(d.out, '}')
+}
+
+// pointerAppend adds a reference token to a JSON Pointer, escaping '~' and '/'.
+func pointerAppend(pointer, token string) string {
+	if strings.ContainsAny(token, "~/") {
+		token = strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+	}
+	return pointer + "/" + token
+}
@@ -0,0 +1,146 @@
+package jsonparser
+
+import (
+	"strings"
+	"testing"
+)
+
+var diffTests = []struct {
+	desc     string
+	a        string
+	b        string
+	opts     []DiffOption
+	expected string
+}{
+	{
+		desc:     "equal documents",
+		a:        `{"a":1,"b":[1,2]}`,
+		b:        ` {"b":[1,2.0],"a":1}`,
+		expected: `[]`,
+	},
+	{
+		desc:     "object members",
+		a:        `{"a":1,"b":2,"c":{"d":3}}`,
+		b:        `{"a":1,"c":{"d":4},"e":5}`,
+		expected: `[{"op":"remove","path":"/b"},{"op":"replace","path":"/c/d","value":4},{"op":"add","path":"/e","value":5}]`,
+	},
+	{
+		desc:     "type changes replace the value",
+		a:        `{"a":{"b":1}}`,
+		b:        `{"a":[1]}`,
+		expected: `[{"op":"replace","path":"/a","value":[1]}]`,
+	},
+	{
+		desc:     "whole document",
+		a:        `1`,
+		b:        `"x"`,
+		expected: `[{"op":"replace","path":"","value":"x"}]`,
+	},
+	{
+		desc:     "arrays by index",
+		a:        `[1,2,3,4]`,
+		b:        `[1,5]`,
+		expected: `[{"op":"replace","path":"/1","value":5},{"op":"remove","path":"/3"},{"op":"remove","path":"/2"}]`,
+	},
+	{
+		desc:     "growing arrays",
+		a:        `{"l":[1]}`,
+		b:        `{"l":[1,{"x":2},3]}`,
+		expected: `[{"op":"add","path":"/l/-","value":{"x":2}},{"op":"add","path":"/l/-","value":3}]`,
+	},
+	{
+		desc:     "pointer escaping",
+		a:        `{"a/b":1,"c~d":2}`,
+		b:        `{"a/b":2,"c~d":3}`,
+		expected: `[{"op":"replace","path":"/a~1b","value":2},{"op":"replace","path":"/c~0d","value":3}]`,
+	},
+	{
+		desc:     "escaped keys are compared unescaped",
+		a:        `{"\u0061":1}`,
+		b:        `{"a":1}`,
+		expected: `[]`,
+	},
+	{
+		desc:     "repeated keys read the last value",
+		a:        `{"a":1,"a":2}`,
+		b:        `{"a":2}`,
+		expected: `[]`,
+	},
+	{
+		desc:     "arrays by key",
+		a:        `[{"id":1,"v":"a"},{"id":2,"v":"b"},{"id":3,"v":"c"}]`,
+		b:        `[{"id":3,"v":"c"},{"id":1,"v":"x"},{"id":4,"v":"d"}]`,
+		opts:     []DiffOption{DiffArraysByKey("id")},
+		expected: `[{"op":"remove","path":"/1"},{"op":"move","from":"/1","path":"/0"},{"op":"replace","path":"/1/v","value":"x"},{"op":"add","path":"/2","value":{"id":4,"v":"d"}}]`,
+	},
+	{
+		desc:     "arrays by key fall back to index without keys",
+		a:        `[{"id":1},{"v":2}]`,
+		b:        `[{"v":2},{"id":1}]`,
+		opts:     []DiffOption{DiffArraysByKey("id")},
+		expected: `[{"op":"remove","path":"/0/id"},{"op":"add","path":"/0/v","value":2},{"op":"remove","path":"/1/v"},{"op":"add","path":"/1/id","value":1}]`,
+	},
+}
+
+func TestDiff(t *testing.T) {
+	for _, test := range diffTests {
+		patch, err := Diff([]byte(test.a), []byte(test.b), test.opts...)
+		if err != nil {
+			t.Errorf("Diff test '%s' returned error %v", test.desc, err)
+			continue
+		}
+		if string(patch) != test.expected {
+			t.Errorf("Diff test '%s' expected %s, obtained %s", test.desc, test.expected, patch)
+		}
+
+		// The patch must turn a into b
+		if value, err := ApplyPatch([]byte(test.a), patch); err != nil || !equalValues(value, []byte(test.b)) {
+			t.Errorf("Diff test '%s' patch applied to a returned %s, %v; expected %s", test.desc, value, err, test.b)
+		}
+	}
+}
+
+func TestDiffArraysByKeyRoundTrip(t *testing.T) {
+	docs := []string{
+		`{"items":[{"id":"a","n":1},{"id":"b","n":2},{"id":"c","n":3},{"id":"d","n":4}]}`,
+		`{"items":[{"id":"d","n":4},{"id":"c","n":3},{"id":"b","n":2},{"id":"a","n":1}]}`,
+		`{"items":[{"id":"e","n":5},{"id":"b","n":20},{"id":"a","n":1}]}`,
+		`{"items":[]}`,
+		`{"items":[{"id":"a","n":{"deep":[1,2]}},{"id":"f","n":6}]}`,
+	}
+
+	for _, a := range docs {
+		for _, b := range docs {
+			patch, err := Diff([]byte(a), []byte(b), DiffArraysByKey("id"))
+			if err != nil {
+				t.Errorf("Diff(%s, %s) returned error %v", a, b, err)
+				continue
+			}
+			if value, err := ApplyPatch([]byte(a), patch); err != nil || !equalValues(value, []byte(b)) {
+				t.Errorf("Diff(%s, %s) returned %s, which applied gives %s, %v", a, b, patch, value, err)
+			}
+		}
+	}
+}
+
+func TestDiffDeepNesting(t *testing.T) {
+	// Each level is walked once, rather than compared whole at every level above it
+	const depth = 1000
+	a := strings.Repeat(`{"x":[`, depth) + "1" + strings.Repeat("]}", depth)
+	b := strings.Repeat(`{"x":[`, depth) + "2" + strings.Repeat("]}", depth)
+
+	patch, err := Diff([]byte(a), []byte(b))
+	expected := `[{"op":"replace","path":"` + strings.Repeat("/x/0", depth) + `","value":2}]`
+	if err != nil || string(patch) != expected {
+		t.Errorf("Diff of deeply nested documents returned %.100s..., %v", patch, err)
+	}
+	if patch, err := Diff([]byte(a), []byte(a)); err != nil || string(patch) != "[]" {
+		t.Errorf("Diff of equal deeply nested documents returned %s, %v", patch, err)
+	}
+}
+
+func TestDiffMalformed(t *testing.T) {
+	if _, err := Diff([]byte(`{"a":1}`), []byte(`{"a":[1}`)); err == nil {
+		t.Errorf("Diff should fail on malformed input")
+	}
+}
@@ -0,0 +1,98 @@
+package jsonparser
+
+// DuplicateKeyPolicy selects which member is used when an object repeats a key.
+// RFC 7159 leaves the behavior undefined; encoding/json uses the last occurrence.
+// Its Get, EachKey, Set and Delete methods are shorthand for those of a Parser using only the policy.
+type DuplicateKeyPolicy int
+
+const (
+	// FirstKeyWins uses the first occurrence of a repeated key. This is what the package-level functions do.
+	FirstKeyWins = DuplicateKeyPolicy(iota)
+	// LastKeyWins uses the last occurrence of a repeated key, like encoding/json.
+	LastKeyWins
+	// RejectDuplicateKeys fails with DuplicateKeyError when a key on the requested path is repeated.
+	RejectDuplicateKeys
+)
+
+func (dp DuplicateKeyPolicy) String() string {
+	switch dp {
+	case FirstKeyWins:
+		return "first-wins"
+	case LastKeyWins:
+		return "last-wins"
+	case RejectDuplicateKeys:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
+// Get behaves like the package-level Get, using the policy for repeated keys.
+func (dp DuplicateKeyPolicy) Get(data []byte, keys ...string) (value []byte, dataType ValueType, offset int, err error) {
+	return (&Parser{DuplicateKeys: dp}).Get(data, keys...)
+}
+
+// EachKey behaves like the package-level EachKey, using the policy for repeated keys.
+// Unless the policy is FirstKeyWins every path is resolved separately, so callbacks are invoked in path order.
+func (dp DuplicateKeyPolicy) EachKey(data []byte, cb func(int, []byte, ValueType, error), paths ...[]string) int {
+	return (&Parser{DuplicateKeys: dp}).EachKey(data, cb, paths...)
+}
+
+// Set behaves like the package-level Set, using the policy for repeated keys.
+func (dp DuplicateKeyPolicy) Set(data []byte, setValue []byte, keys ...string) (value []byte, err error) {
+	return (&Parser{DuplicateKeys: dp}).Set(data, setValue, keys...)
+}
+
+// Delete behaves like the package-level Delete, using the policy for repeated keys.
+func (dp DuplicateKeyPolicy) Delete(data []byte, keys ...string) []byte {
+	return (&Parser{DuplicateKeys: dp}).Delete(data, keys...)
+}
+
+// FindDuplicateKeys reports the path of every key that is repeated within its object, in the order the
+// repetitions appear. Each path is reported once, no matter how many times the key is repeated.
+// Paths are built with Key and Index, so the result can be passed back to Get or Delete.
+func FindDuplicateKeys(data []byte) ([][]string, error) {
+	value, dataType, _, err := Get(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var dups [][]string
+	if err := findDuplicateKeys(value, dataType, nil, &dups); err != nil {
+		return nil, err
+	}
+
+	return dups, nil
+}
+
+func findDuplicateKeys(value []byte, dataType ValueType, path []string, dups *[][]string) error {
+	switch dataType {
+	case Object:
+		seen := make(map[string]int)
+		return ObjectEach(value, func(key []byte, v []byte, dt ValueType, offset int) error {
+			k := string(key)
+			keyPath := append(path[:len(path):len(path)], Key(k))
+
+			if seen[k]++; seen[k] == 2 {
+				*dups = append(*dups, keyPath)
+			}
+
+			return findDuplicateKeys(v, dt, keyPath, dups)
+		})
+	case Array:
+		var idx int
+		var walkErr error
+		_, err := ArrayEach(value, func(v []byte, dt ValueType, offset int, err error) {
+			if walkErr == nil {
+				walkErr = findDuplicateKeys(v, dt, append(path[:len(path):len(path)], Index(idx)), dups)
+			}
+			idx++
+		})
+		if err != nil {
+			return err
+		}
+		return walkErr
+	}
+
+	return nil
+}
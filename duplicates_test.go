@@ -0,0 +1,279 @@
+package jsonparser
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+var duplicateJson = `{"a":1,"b":{"c":"first","c":"second"},"a":2,"arr":[{"d":true,"d":false}]}`
+
+var lastKeyWinsGetTests = []GetTest{
+	{
+		desc:    "last top level duplicate",
+		json:    duplicateJson,
+		path:    []string{"a"},
+		isFound: true,
+		data:    `2`,
+	},
+	{
+		desc:    "last nested duplicate",
+		json:    duplicateJson,
+		path:    []string{"b", "c"},
+		isFound: true,
+		data:    `second`,
+	},
+	{
+		desc:    "last duplicate within array element",
+		json:    duplicateJson,
+		path:    []string{"arr", "[0]", "d"},
+		isFound: true,
+		data:    `false`,
+	},
+	{
+		desc:    "unique key",
+		json:    duplicateJson,
+		path:    []string{"arr", "[0]"},
+		isFound: true,
+		data:    `{"d":true,"d":false}`,
+	},
+	{
+		desc:    "missing key",
+		json:    duplicateJson,
+		path:    []string{"b", "x"},
+		isFound: false,
+	},
+	{
+		desc:    "missing array index",
+		json:    duplicateJson,
+		path:    []string{"arr", "[1]"},
+		isFound: false,
+	},
+	{
+		desc:    "empty object",
+		json:    `{"a":{}}`,
+		path:    []string{"a", "b"},
+		isFound: false,
+	},
+	{
+		desc:  "malformed object",
+		json:  `{"a":1 "a":2}`,
+		path:  []string{"a"},
+		isErr: true,
+	},
+}
+
+var rejectDuplicateKeysGetTests = []GetTest{
+	{
+		desc:  "duplicate on path",
+		json:  duplicateJson,
+		path:  []string{"a"},
+		isErr: true,
+	},
+	{
+		desc:  "duplicate nested on path",
+		json:  `{"b":{"c":1,"c":2}}`,
+		path:  []string{"b", "c"},
+		isErr: true,
+	},
+	{
+		desc:    "duplicate off path",
+		json:    `{"b":{"c":1,"c":2},"e":3}`,
+		path:    []string{"e"},
+		isFound: true,
+		data:    `3`,
+	},
+}
+
+func TestLastKeyWinsGet(t *testing.T) {
+	runGetTests(t, "LastKeyWins.Get()", lastKeyWinsGetTests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, dataType, _, err = LastKeyWins.Get([]byte(test.json), test.path...)
+			return
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := []byte(test.data.(string))
+			return bytes.Equal(expected, value.([]byte)), expected
+		},
+	)
+}
+
+func TestRejectDuplicateKeysGet(t *testing.T) {
+	runGetTests(t, "RejectDuplicateKeys.Get()", rejectDuplicateKeysGetTests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, dataType, _, err = RejectDuplicateKeys.Get([]byte(test.json), test.path...)
+			return
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := []byte(test.data.(string))
+			return bytes.Equal(expected, value.([]byte)), expected
+		},
+	)
+
+	if _, _, _, err := RejectDuplicateKeys.Get([]byte(duplicateJson), "a"); err != DuplicateKeyError {
+		t.Errorf("Expected DuplicateKeyError, obtained %v", err)
+	}
+}
+
+func TestFirstKeyWinsMatchesGet(t *testing.T) {
+	v, _, _, _ := FirstKeyWins.Get([]byte(duplicateJson), "a")
+	if string(v) != "1" {
+		t.Errorf("Expected first value, obtained %s", v)
+	}
+}
+
+func TestLastKeyWinsEachKey(t *testing.T) {
+	paths := [][]string{
+		{"a"},
+		{"b", "c"},
+		{"missing"},
+		{"arr", "[0]", "d"},
+	}
+	expected := map[int]string{0: "2", 1: "second", 3: "false"}
+
+	found := make(map[int]string)
+	LastKeyWins.EachKey([]byte(duplicateJson), func(idx int, value []byte, vt ValueType, err error) {
+		found[idx] = string(value)
+	}, paths...)
+
+	if !reflect.DeepEqual(expected, found) {
+		t.Errorf("Expected %v, obtained %v", expected, found)
+	}
+}
+
+var lastKeyWinsSetTests = []SetTest{
+	{
+		desc:    "set last duplicate",
+		json:    `{"a":1,"a":2}`,
+		path:    []string{"a"},
+		setData: `3`,
+		isFound: true,
+		data:    `{"a":1,"a":3}`,
+	},
+	{
+		desc:    "set below last duplicate",
+		json:    `{"a":{"b":1},"a":{"b":2}}`,
+		path:    []string{"a", "c"},
+		setData: `3`,
+		isFound: true,
+		data:    `{"a":{"b":1},"a":{"b":2,"c":3}}`,
+	},
+	{
+		desc:    "set new key",
+		json:    `{"a":1,"a":2}`,
+		path:    []string{"b"},
+		setData: `3`,
+		isFound: true,
+		data:    `{"a":1,"a":2,"b":3}`,
+	},
+}
+
+func TestLastKeyWinsSet(t *testing.T) {
+	runSetTests(t, "LastKeyWins.Set()", lastKeyWinsSetTests,
+		func(test SetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = LastKeyWins.Set([]byte(test.json), []byte(test.setData), test.path...)
+			return
+		},
+		func(test SetTest, value interface{}) (bool, interface{}) {
+			expected := []byte(test.data.(string))
+			return bytes.Equal(expected, value.([]byte)), expected
+		},
+	)
+}
+
+func TestRejectDuplicateKeysSet(t *testing.T) {
+	if _, err := RejectDuplicateKeys.Set([]byte(`{"a":{},"a":{}}`), []byte(`1`), "a", "b"); err != DuplicateKeyError {
+		t.Errorf("Expected DuplicateKeyError, obtained %v", err)
+	}
+}
+
+var lastKeyWinsDeleteTests = []DeleteTest{
+	{
+		desc: "delete last duplicate",
+		json: `{"a":1,"b":2,"a":3}`,
+		path: []string{"a"},
+		data: `{"a":1,"b":2}`,
+	},
+	{
+		desc: "delete nested last duplicate",
+		json: `{"a":{"b":1,"b":2,"c":3}}`,
+		path: []string{"a", "b"},
+		data: `{"a":{"b":1,"c":3}}`,
+	},
+	{
+		desc: "delete array element",
+		json: `{"a":[1,2,3]}`,
+		path: []string{"a", "[1]"},
+		data: `{"a":[1,3]}`,
+	},
+	{
+		desc: "delete missing key",
+		json: `{"a":1,"a":2}`,
+		path: []string{"b"},
+		data: `{"a":1,"a":2}`,
+	},
+}
+
+func TestLastKeyWinsDelete(t *testing.T) {
+	runDeleteTests(t, "LastKeyWins.Delete()", lastKeyWinsDeleteTests,
+		func(test DeleteTest) interface{} {
+			return LastKeyWins.Delete([]byte(test.json), test.path...)
+		},
+		func(test DeleteTest, value interface{}) (bool, interface{}) {
+			expected := []byte(test.data.(string))
+			return bytes.Equal(expected, value.([]byte)), expected
+		},
+	)
+}
+
+func TestFindDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		desc  string
+		json  string
+		dups  [][]string
+		isErr bool
+	}{
+		{
+			desc: "no duplicates",
+			json: `{"a":1,"b":{"a":1},"c":[{"a":1},{"a":1}]}`,
+		},
+		{
+			desc: "duplicates at every level",
+			json: duplicateJson,
+			dups: [][]string{{"b", "c"}, {"a"}, {"arr", "[0]", "d"}},
+		},
+		{
+			desc: "key repeated three times is reported once",
+			json: `[{"x":1,"x":2,"x":3}]`,
+			dups: [][]string{{"[0]", "x"}},
+		},
+		{
+			desc: "escaped keys are compared unescaped",
+			json: `{"a":1,"\u0061":2}`,
+			dups: [][]string{{"a"}},
+		},
+		{
+			desc: "keys that look like indexes are marked",
+			json: `{"[0]":{"x":1,"x":2}}`,
+			dups: [][]string{{Key("[0]"), "x"}},
+		},
+		{
+			desc: "scalar document",
+			json: `1`,
+		},
+		{
+			desc:  "malformed document",
+			json:  `{"a":[1,}`,
+			isErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		dups, err := FindDuplicateKeys([]byte(test.json))
+		if (err != nil) != test.isErr {
+			t.Errorf("FindDuplicateKeys test '%s' isErr mismatch: expected %t, obtained %v", test.desc, test.isErr, err)
+		} else if !reflect.DeepEqual(test.dups, dups) {
+			t.Errorf("FindDuplicateKeys test '%s' expected %v, obtained %v", test.desc, test.dups, dups)
+		}
+	}
+}
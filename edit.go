@@ -0,0 +1,479 @@
+package jsonparser
+
+import (
+	"sort"
+)
+
+// Editor collects Set and Delete operations on a document, to be applied together by Apply.
+// Every path is resolved against the original document, in one walk over the objects and arrays
+// the paths go through, and the result is written once however many operations there are.
+//
+// Operations on the same path replace each other, the last one added winning. A path that continues
+// another one, such as "a" and "a", "b", makes Apply fail with EditConflictError. Append and Prepend
+// segments never overlap: each adds an element of its own, in the order the operations were added.
+type Editor struct {
+	data []byte
+	ops  []editOp
+	p    *Parser
+}
+
+type editOp struct {
+	keys  []string
+	value []byte
+	del   bool
+}
+
+// Edit returns an Editor for data. data is never modified.
+func Edit(data []byte) *Editor {
+	return defaultParser.Edit(data)
+}
+
+// Set queues setting the value at keys, creating missing objects and arrays like the package-level Set.
+func (e *Editor) Set(setValue []byte, keys ...string) *Editor {
+	e.ops = append(e.ops, editOp{keys: keys, value: setValue})
+	return e
+}
+
+// Delete queues deleting the value at keys. Paths that do not exist are ignored, like the package-level Delete.
+func (e *Editor) Delete(keys ...string) *Editor {
+	e.ops = append(e.ops, editOp{keys: keys, del: true})
+	return e
+}
+
+// Apply returns a new document with all queued operations applied.
+func (e *Editor) Apply() ([]byte, error) {
+	if err := e.p.check(e.data); err != nil {
+		return nil, err
+	}
+
+	return e.p.checked(e.apply())
+}
+
+// apply implements Apply, leaving the checks of the parser to it.
+func (e *Editor) apply() ([]byte, error) {
+	size := len(e.data)
+	ops := make([]*editOp, len(e.ops))
+	for i := range e.ops {
+		ops[i] = &e.ops[i]
+		size += len(e.ops[i].value)
+		if len(e.ops[i].keys) == 0 && !e.ops[i].del {
+			return nil, KeyPathNotFoundError
+		}
+	}
+
+	direct, nested, err := splitEditOps(ops, 0)
+	if err != nil {
+		return nil, err
+	} else if direct != nil {
+		// Deleting the whole document, like Delete without keys
+		return []byte{}, nil
+	}
+
+	start := nextToken(e.data)
+	if start == -1 {
+		return nil, MalformedJsonError
+	}
+	_, _, end, err := getType(e.data, start)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, size)
+	out = append(out, e.data[:start]...)
+	if out, err = editValue(out, e.data, start, end, nested, 0); err != nil {
+		return nil, err
+	}
+	return append(out, e.data[end:]...), nil
+}
+
+// DeleteMany deletes every path in paths in one pass over data, like calling Delete for each of them on the
+// original document. Paths that do not exist are ignored, and paths inside another deleted value are
+// deleted along with it. data is never modified.
+func DeleteMany(data []byte, paths ...[]string) ([]byte, error) {
+	return defaultParser.deleteMany(data, paths...)
+}
+
+// deleteMany implements DeleteMany with an Editor of p.
+func (p *Parser) deleteMany(data []byte, paths ...[]string) ([]byte, error) {
+	e := p.Edit(data)
+	for _, path := range paths {
+		if !hasDeletedParent(path, paths) {
+			e.Delete(path...)
+		}
+	}
+	return e.Apply()
+}
+
+// hasDeletedParent reports whether a path in paths is a proper prefix of path.
+func hasDeletedParent(path []string, paths [][]string) bool {
+	for _, parent := range paths {
+		if len(parent) >= len(path) {
+			continue
+		}
+
+		prefix := true
+		for i, seg := range parent {
+			if seg != path[i] {
+				prefix = false
+				break
+			}
+		}
+		if prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// splitEditOps splits operations whose paths agree up to depth into the last one addressing the value
+// at depth itself, and those addressing values nested in it. Having both is a conflict.
+func splitEditOps(ops []*editOp, depth int) (direct *editOp, nested []*editOp, err error) {
+	for _, op := range ops {
+		if len(op.keys) == depth {
+			direct = op
+		} else {
+			nested = append(nested, op)
+		}
+	}
+
+	if direct != nil && len(nested) > 0 {
+		return nil, nil, EditConflictError
+	}
+	return direct, nested, nil
+}
+
+// createsValue reports whether operations on a missing member or element create it, rather than only deleting.
+func createsValue(ops []*editOp) bool {
+	for _, op := range ops {
+		if !op.del {
+			return true
+		}
+	}
+	return false
+}
+
+// editValue appends data[start:end] to out with ops applied to its members or elements.
+// All ops have paths longer than depth.
+func editValue(out, data []byte, start, end int, ops []*editOp, depth int) ([]byte, error) {
+	if len(ops) == 0 {
+		return append(out, data[start:end]...), nil
+	}
+
+	switch data[start] {
+	case '{':
+		return editObject(out, data, start, end, ops, depth)
+	case '[':
+		return editArray(out, data, start, end, ops, depth)
+	}
+
+	// Like Set, replace values on the path that are not objects or arrays
+	if !createsValue(ops) {
+		return append(out, data[start:end]...), nil
+	}
+	return newValue(out, ops, depth)
+}
+
+// newValue appends the value created by ops, all of which have paths at least depth long, in place of a missing one.
+func newValue(out []byte, ops []*editOp, depth int) ([]byte, error) {
+	direct, nested, err := splitEditOps(ops, depth)
+	if err != nil {
+		return nil, err
+	} else if direct != nil {
+		return append(out, direct.value...), nil
+	}
+
+	empty := []byte("{}")
+	for _, op := range nested {
+		if !op.del {
+			if isArray, _ := isValidArrayIndex(op.keys[depth]); isArray {
+				empty = []byte("[]")
+			}
+			break
+		}
+	}
+	return editValue(out, empty, 0, len(empty), nested, depth)
+}
+
+// editSpan locates a member or element within an object or array.
+type editSpan struct {
+	keyOffset, offset, endOffset int
+	key                          []byte
+}
+
+// containerSpans returns the members of the object, or the elements of the array, starting at data[start].
+// For array elements keyOffset is the same as offset.
+func containerSpans(data []byte, start int) ([]editSpan, error) {
+	isObject := data[start] == '{'
+	closing, malformed := byte(']'), MalformedArrayError
+	if isObject {
+		closing, malformed = '}', MalformedObjectError
+	}
+
+	var spans []editSpan
+	offset := start + 1
+	for {
+		off := nextToken(data[offset:])
+		if off == -1 {
+			return nil, malformed
+		}
+		offset += off
+		if data[offset] == closing && len(spans) == 0 {
+			return nil, nil
+		}
+
+		s := editSpan{keyOffset: offset}
+		if isObject {
+			if data[offset] != '"' {
+				return nil, MalformedObjectError
+			}
+			strEnd, keyEscaped := stringEnd(data[offset+1:])
+			if strEnd == -1 {
+				return nil, MalformedStringError
+			}
+			s.key = data[offset+1 : offset+strEnd]
+			if keyEscaped {
+				ku, err := Unescape(s.key, nil)
+				if err != nil {
+					return nil, MalformedStringEscapeError
+				}
+				s.key = ku
+			}
+			offset += strEnd + 1
+
+			if off = nextToken(data[offset:]); off == -1 || data[offset+off] != ':' {
+				return nil, MalformedJsonError
+			}
+			offset += off + 1
+			if off = nextToken(data[offset:]); off == -1 {
+				return nil, MalformedJsonError
+			}
+			offset += off
+		}
+
+		s.offset = offset
+		_, _, end, err := getType(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		s.endOffset = end
+		spans = append(spans, s)
+		offset = end
+
+		// Skip over the comma, or stop at the closing brace or bracket
+		if off = nextToken(data[offset:]); off == -1 {
+			return nil, malformed
+		}
+		offset += off
+		if data[offset] == closing {
+			return spans, nil
+		} else if data[offset] != ',' {
+			return nil, malformed
+		}
+		offset++
+	}
+}
+
+// editOpGroup holds the operations addressing one member or element.
+type editOpGroup struct {
+	seg    string
+	ops    []*editOp
+	member int
+}
+
+// editObject is editValue for objects. Operations on a repeated key apply to its first occurrence, like Get.
+func editObject(out, data []byte, start, end int, ops []*editOp, depth int) ([]byte, error) {
+	spans, err := containerSpans(data, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group operations by the member they address, in the order the members are first addressed
+	var groups []*editOpGroup
+	byName := make(map[string]*editOpGroup)
+	for _, op := range ops {
+		name := segmentName(op.keys[depth])
+		g := byName[name]
+		if g == nil {
+			g = &editOpGroup{seg: op.keys[depth], member: -1}
+			byName[name] = g
+			groups = append(groups, g)
+		}
+		g.ops = append(g.ops, op)
+	}
+
+	memberGroups := make([]*editOpGroup, len(spans))
+	for i, s := range spans {
+		if g := byName[string(s.key)]; g != nil && g.member == -1 {
+			g.member = i
+			memberGroups[i] = g
+		}
+	}
+
+	header, trailer := containerEnds(data, start, end, spans)
+	out = append(out, header...)
+	written := 0
+
+	for i, s := range spans {
+		var direct *editOp
+		var nested []*editOp
+		if g := memberGroups[i]; g != nil {
+			if direct, nested, err = splitEditOps(g.ops, depth+1); err != nil {
+				return nil, err
+			} else if direct != nil && direct.del {
+				continue
+			}
+		}
+
+		out = appendSeparator(out, data, spans, i, written)
+		out = append(out, data[s.keyOffset:s.offset]...)
+		if direct != nil {
+			out = append(out, direct.value...)
+		} else if out, err = editValue(out, data, s.offset, s.endOffset, nested, depth+1); err != nil {
+			return nil, err
+		}
+		written++
+	}
+
+	// Add the members that do not exist yet
+	for _, g := range groups {
+		if g.member != -1 || !createsValue(g.ops) {
+			continue
+		}
+		if isArray, _ := isValidArrayIndex(g.seg); isArray {
+			return nil, KeyPathNotFoundError
+		}
+
+		if written > 0 {
+			out = append(out, ',')
+		}
+		out = AppendQuoted(out, segmentName(g.seg))
+		out = append(out, ':')
+		if out, err = newValue(out, g.ops, depth+1); err != nil {
+			return nil, err
+		}
+		written++
+	}
+
+	return append(out, trailer...), nil
+}
+
+// editArray is editValue for arrays.
+func editArray(out, data []byte, start, end int, ops []*editOp, depth int) ([]byte, error) {
+	spans, err := containerSpans(data, start)
+	if err != nil {
+		return nil, err
+	}
+
+	var prepends, appends []*editOp
+	byIndex := make(map[int][]*editOp)
+	for _, op := range ops {
+		seg := op.keys[depth]
+		switch isArray, idx := isValidArrayIndex(seg); {
+		case seg == "[+]":
+			appends = append(appends, op)
+		case seg == "[-]":
+			prepends = append(prepends, op)
+		case isArray:
+			byIndex[idx] = append(byIndex[idx], op)
+		case !op.del:
+			return nil, KeyPathNotFoundError
+		}
+	}
+
+	header, trailer := containerEnds(data, start, end, spans)
+	out = append(out, header...)
+	written := 0
+
+	// Each prepended element goes before the ones prepended earlier
+	for i := len(prepends) - 1; i >= 0; i-- {
+		if out, written, err = appendNewElement(out, prepends[i:i+1], depth, written); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, s := range spans {
+		var direct *editOp
+		var nested []*editOp
+		if ops := byIndex[i]; ops != nil {
+			if direct, nested, err = splitEditOps(ops, depth+1); err != nil {
+				return nil, err
+			} else if direct != nil && direct.del {
+				continue
+			}
+		}
+
+		out = appendSeparator(out, data, spans, i, written)
+		if direct != nil {
+			out = append(out, direct.value...)
+		} else if out, err = editValue(out, data, s.offset, s.endOffset, nested, depth+1); err != nil {
+			return nil, err
+		}
+		written++
+	}
+
+	// Set elements past the end, padding with nulls like Set
+	var missing []int
+	for idx, ops := range byIndex {
+		if idx >= len(spans) && createsValue(ops) {
+			missing = append(missing, idx)
+		}
+	}
+	sort.Ints(missing)
+
+	next := len(spans)
+	for _, idx := range missing {
+		for ; next < idx; next++ {
+			if written > 0 {
+				out = append(out, ',')
+			}
+			out = append(out, nullLiteral...)
+			written++
+		}
+		if out, written, err = appendNewElement(out, byIndex[idx], depth, written); err != nil {
+			return nil, err
+		}
+		next++
+	}
+
+	for i := range appends {
+		if out, written, err = appendNewElement(out, appends[i:i+1], depth, written); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(out, trailer...), nil
+}
+
+// appendNewElement appends the element created by ops, preceded by a comma unless it comes first.
+func appendNewElement(out []byte, ops []*editOp, depth, written int) ([]byte, int, error) {
+	if !createsValue(ops) {
+		return out, written, nil
+	}
+
+	if written > 0 {
+		out = append(out, ',')
+	}
+	out, err := newValue(out, ops, depth+1)
+	return out, written + 1, err
+}
+
+// containerEnds returns the text of an object or array before its first member and after its last.
+func containerEnds(data []byte, start, end int, spans []editSpan) (header, trailer []byte) {
+	if len(spans) == 0 {
+		return data[start : start+1], data[start+1 : end]
+	}
+	return data[start:spans[0].keyOffset], data[spans[len(spans)-1].endOffset:end]
+}
+
+// appendSeparator appends what separates spans[i] from the members written before it, keeping the original
+// whitespace where there is one.
+func appendSeparator(out, data []byte, spans []editSpan, i, written int) []byte {
+	switch {
+	case written == 0:
+		return out
+	case i == 0:
+		return append(out, ',')
+	default:
+		return append(out, data[spans[i-1].endOffset:spans[i].keyOffset]...)
+	}
+}
@@ -0,0 +1,305 @@
+package jsonparser
+
+import (
+	"testing"
+)
+
+var editTests = []struct {
+	desc     string
+	json     string
+	edit     func(e *Editor) *Editor
+	expected string
+	err      error
+}{
+	{
+		desc:     "no operations",
+		json:     `{"a":1}`,
+		edit:     func(e *Editor) *Editor { return e },
+		expected: `{"a":1}`,
+	},
+	{
+		desc: "set and delete siblings",
+		json: `{"a":1,"b":2,"c":3}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`10`), "a").Delete("b").Set([]byte(`"x"`), "d")
+		},
+		expected: `{"a":10,"c":3,"d":"x"}`,
+	},
+	{
+		desc: "delete every member",
+		json: `{"a":1,"b":2}`,
+		edit: func(e *Editor) *Editor {
+			return e.Delete("b").Delete("a")
+		},
+		expected: `{}`,
+	},
+	{
+		desc: "delete first and last members keeps whitespace",
+		json: "{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3\n}",
+		edit: func(e *Editor) *Editor {
+			return e.Delete("a").Delete("c")
+		},
+		expected: "{\n  \"b\": 2\n}",
+	},
+	{
+		desc: "nested edits in several branches",
+		json: `{"a":{"x":1,"y":2},"b":[1,2,3],"c":{"z":{"w":true}}}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`3`), "a", "y").Delete("b", "[1]").Set([]byte(`false`), "c", "z", "w").Set([]byte(`4`), "b", "[0]")
+		},
+		expected: `{"a":{"x":1,"y":3},"b":[4,3],"c":{"z":{"w":false}}}`,
+	},
+	{
+		desc: "new keys are escaped",
+		json: `{}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`1`), "a\"b", "c\\d")
+		},
+		expected: `{"a\"b":{"c\\d":1}}`,
+	},
+	{
+		desc: "create nested objects and arrays",
+		json: `{}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`1`), "a", "b").Set([]byte(`2`), "a", "c").Set([]byte(`3`), "l", "[1]")
+		},
+		expected: `{"a":{"b":1,"c":2},"l":[null,3]}`,
+	},
+	{
+		desc: "set past the end of an array pads with null",
+		json: `[1]`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`5`), "[4]").Set([]byte(`2`), "[2]")
+		},
+		expected: `[1,null,2,null,5]`,
+	},
+	{
+		desc: "append and prepend",
+		json: `{"l":[1,2]}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`3`), "l", Append()).Set([]byte(`0`), "l", Prepend()).Set([]byte(`4`), "l", Append()).Set([]byte(`-1`), "l", Prepend())
+		},
+		expected: `{"l":[-1,0,1,2,3,4]}`,
+	},
+	{
+		desc: "append to an empty array and delete the only element",
+		json: `{"l":[],"m":[1]}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`{"a":1}`), "l", Append()).Set([]byte(`2`), "l", Append(), "b").Delete("m", "[0]")
+		},
+		expected: `{"l":[{"a":1},{"b":2}],"m":[]}`,
+	},
+	{
+		desc: "later operation on the same path wins",
+		json: `{"a":1,"b":2}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`10`), "a").Delete("a").Delete("b").Set([]byte(`20`), "b")
+		},
+		expected: `{"b":20}`,
+	},
+	{
+		desc: "typed keys address the same member",
+		json: `{"[0]":1}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`2`), Key("[0]"))
+		},
+		expected: `{"[0]":2}`,
+	},
+	{
+		desc: "scalars on the path are replaced",
+		json: `{"a":1}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`2`), "a", "b")
+		},
+		expected: `{"a":{"b":2}}`,
+	},
+	{
+		desc: "deleting missing paths does nothing",
+		json: `{"a":1,"l":[1]}`,
+		edit: func(e *Editor) *Editor {
+			return e.Delete("b").Delete("a", "b").Delete("l", "[3]").Delete("x", "y")
+		},
+		expected: `{"a":1,"l":[1]}`,
+	},
+	{
+		desc: "first of repeated keys is edited",
+		json: `{"a":1,"a":2}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`3`), "a")
+		},
+		expected: `{"a":3,"a":2}`,
+	},
+	{
+		desc: "escaped keys are matched unescaped",
+		json: `{"\u0061":1}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`2`), "a")
+		},
+		expected: `{"\u0061":2}`,
+	},
+	{
+		desc: "surrounding whitespace is kept",
+		json: " { \"a\" : 1 } \n",
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`2`), "a")
+		},
+		expected: " { \"a\" : 2 } \n",
+	},
+	{
+		desc: "delete the whole document",
+		json: `{"a":1}`,
+		edit: func(e *Editor) *Editor {
+			return e.Delete()
+		},
+		expected: ``,
+	},
+	{
+		desc: "nested paths conflict",
+		json: `{"a":{"b":1}}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`1`), "a").Set([]byte(`2`), "a", "b")
+		},
+		err: EditConflictError,
+	},
+	{
+		desc: "nested paths conflict when the parent is deleted",
+		json: `{"a":{"b":1}}`,
+		edit: func(e *Editor) *Editor {
+			return e.Delete("a", "b").Delete("a")
+		},
+		err: EditConflictError,
+	},
+	{
+		desc: "set without keys",
+		json: `{}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`1`))
+		},
+		err: KeyPathNotFoundError,
+	},
+	{
+		desc: "index into an object",
+		json: `{"a":{}}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`1`), "a", "[0]")
+		},
+		err: KeyPathNotFoundError,
+	},
+	{
+		desc: "key into an array",
+		json: `{"a":[]}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`1`), "a", "b")
+		},
+		err: KeyPathNotFoundError,
+	},
+	{
+		desc: "malformed object on the path",
+		json: `{"a":{"b" 1}}`,
+		edit: func(e *Editor) *Editor {
+			return e.Set([]byte(`1`), "a", "b")
+		},
+		err: MalformedJsonError,
+	},
+}
+
+func TestEdit(t *testing.T) {
+	for _, test := range editTests {
+		data := []byte(test.json)
+		value, err := test.edit(Edit(data)).Apply()
+
+		if err != test.err {
+			t.Errorf("Edit test '%s' expected error %v, obtained %v", test.desc, test.err, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("Edit test '%s' expected %s, obtained %s", test.desc, test.expected, value)
+		}
+		if string(data) != test.json {
+			t.Errorf("Edit test '%s' modified its input: %s", test.desc, data)
+		}
+	}
+}
+
+// Edits that do not depend on each other give the same result as applying Set and Delete in turn.
+func TestEditMatchesSequential(t *testing.T) {
+	data := []byte(`{"person":{"name":{"first":"Leonid","last":"Bugaev"},"github":{"handle":"buger","followers":109}},"company":{"name":"Acme"},"tags":["a","b","c"]}`)
+
+	expected, _ := Set(data, []byte(`"Leo"`), "person", "name", "first")
+	expected, _ = Set(expected, []byte(`110`), "person", "github", "followers")
+	expected = Delete(expected, "company", "name")
+	expected = Delete(expected, "tags", "[1]")
+	expected, _ = Set(expected, []byte(`true`), "person", "active")
+
+	value, err := Edit(data).
+		Set([]byte(`"Leo"`), "person", "name", "first").
+		Set([]byte(`110`), "person", "github", "followers").
+		Delete("company", "name").
+		Delete("tags", "[1]").
+		Set([]byte(`true`), "person", "active").
+		Apply()
+
+	if err != nil || string(value) != string(expected) {
+		t.Errorf("Edit returned %s, %v; expected %s", value, err, expected)
+	}
+}
+
+func TestDeleteMany(t *testing.T) {
+	tests := []struct {
+		desc     string
+		json     string
+		paths    [][]string
+		expected string
+	}{
+		{
+			desc:     "siblings",
+			json:     `{"a":1,"b":2,"c":3}`,
+			paths:    [][]string{{"a"}, {"c"}},
+			expected: `{"b":2}`,
+		},
+		{
+			desc:     "every member keeps the object valid",
+			json:     `{"a":1, "b":2}`,
+			paths:    [][]string{{"b"}, {"a"}},
+			expected: `{}`,
+		},
+		{
+			desc:     "array elements by their original indexes",
+			json:     `{"l":[0,1,2,3]}`,
+			paths:    [][]string{{"l", "[0]"}, {"l", "[2]"}, {"l", "[3]"}},
+			expected: `{"l":[1]}`,
+		},
+		{
+			desc:     "paths inside a deleted value",
+			json:     `{"a":{"b":1},"c":2}`,
+			paths:    [][]string{{"a", "b"}, {"a"}},
+			expected: `{"c":2}`,
+		},
+		{
+			desc:     "missing paths are ignored",
+			json:     `{"a":1,"b":[1]}`,
+			paths:    [][]string{{"x"}, {"b", "[3]"}, {"a", "y"}, {"b"}},
+			expected: `{"a":1}`,
+		},
+		{
+			desc:     "repeated paths",
+			json:     `{"a":1,"b":2}`,
+			paths:    [][]string{{"a"}, {"a"}},
+			expected: `{"b":2}`,
+		},
+		{
+			desc:     "no paths",
+			json:     `{"a":1}`,
+			expected: `{"a":1}`,
+		},
+	}
+
+	for _, test := range tests {
+		data := []byte(test.json)
+		value, err := DeleteMany(data, test.paths...)
+		if err != nil || string(value) != test.expected {
+			t.Errorf("DeleteMany test '%s' returned %s, %v; expected %s", test.desc, value, err, test.expected)
+		}
+		if string(data) != test.json {
+			t.Errorf("DeleteMany test '%s' modified its input: %s", test.desc, data)
+		}
+	}
+}
@@ -18,6 +18,12 @@ func combineUTF16Surrogates(high, low rune) rune {
 	return supplementalPlanesOffset + (high-highSurrogateOffset)<<10 + (low - lowSurrogateOffset)
 }
 
+// splitUTF16Surrogates is the inverse of combineUTF16Surrogates, for runes outside the Basic Multilingual Plane.
+func splitUTF16Surrogates(r rune) (high, low rune) {
+	r -= supplementalPlanesOffset
+	return highSurrogateOffset + (r>>10)&0x3FF, lowSurrogateOffset + r&0x3FF
+}
+
 const badHex = -1
 
 func h2I(c byte) int {
@@ -171,3 +177,107 @@ func Unescape(in, out []byte) ([]byte, error) {
 	// Trim the out buffer to the amount that was actually emitted
 	return out[:len(out)-len(buf)], nil
 }
+
+// UnescapeInPlace unescapes b into its own memory and returns the unescaped prefix of b.
+// This never allocates, as unescaping never makes a string longer. The bytes of b past the returned
+// slice are left undefined, and on error so is all of b.
+func UnescapeInPlace(b []byte) ([]byte, error) {
+	return Unescape(b, b[:0])
+}
+
+// EscapeFlags select optional escapes when encoding strings. Without any, only what JSON requires is escaped.
+type EscapeFlags uint8
+
+const (
+	// EscapeHTML escapes '<', '>' and '&', so the output can be embedded in HTML <script> tags.
+	EscapeHTML = EscapeFlags(1 << iota)
+	// EscapeASCII escapes every non-ASCII character as \uXXXX, using surrogate pairs outside the Basic Multilingual Plane.
+	EscapeASCII
+	// EscapeLineTerminators escapes U+2028 and U+2029, which JavaScript before ES2019 does not accept in string literals.
+	EscapeLineTerminators
+)
+
+const lowerHex = "0123456789abcdef"
+
+// charEscapeTable: when byte X must be escaped and has a short form, it is written as '\' followed by charEscapeTable[X]
+var charEscapeTable = [...]byte{
+	'"':  '"',
+	'\\': '\\',
+	'\b': 'b',
+	'\f': 'f',
+	'\n': 'n',
+	'\r': 'r',
+	'\t': 't',
+}
+
+// Escape appends the JSON escaped form of s to dst, without surrounding quotes, and returns the extended buffer.
+// It is the inverse of Unescape. Invalid UTF-8 is replaced with U+FFFD, like encoding/json does.
+func Escape(dst []byte, s string) []byte {
+	return EscapeWithFlags(dst, s, 0)
+}
+
+// AppendQuoted appends s to dst as a quoted JSON string and returns the extended buffer.
+// Unlike strconv.Quote it produces JSON escapes, never Go-only ones such as \x00 or \U0001F603.
+func AppendQuoted(dst []byte, s string) []byte {
+	return AppendQuotedWithFlags(dst, s, 0)
+}
+
+// AppendQuotedWithFlags is AppendQuoted with optional escapes.
+func AppendQuotedWithFlags(dst []byte, s string, flags EscapeFlags) []byte {
+	dst = append(dst, '"')
+	dst = EscapeWithFlags(dst, s, flags)
+	return append(dst, '"')
+}
+
+// EscapeWithFlags is Escape with optional escapes.
+func EscapeWithFlags(dst []byte, s string, flags EscapeFlags) []byte {
+	// Runs of characters that need no escaping are copied in one go
+	start := 0
+
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && (flags&EscapeHTML == 0 || (c != '<' && c != '>' && c != '&')) {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			if int(c) < len(charEscapeTable) && charEscapeTable[c] != 0 {
+				dst = append(dst, '\\', charEscapeTable[c])
+			} else {
+				dst = appendUnicodeEscape(dst, rune(c))
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = appendUnicodeEscape(dst, utf8.RuneError)
+		case flags&EscapeASCII != 0, flags&EscapeLineTerminators != 0 && (r == '\u2028' || r == '\u2029'):
+			dst = append(dst, s[start:i]...)
+			if r > basicMultilingualPlaneOffset {
+				high, low := splitUTF16Surrogates(r)
+				dst = appendUnicodeEscape(dst, high)
+				dst = appendUnicodeEscape(dst, low)
+			} else {
+				dst = appendUnicodeEscape(dst, r)
+			}
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+
+	return append(dst, s[start:]...)
+}
+
+// appendUnicodeEscape appends the \uXXXX escape for a rune in the Basic Multilingual Plane.
+func appendUnicodeEscape(dst []byte, r rune) []byte {
+	return append(dst, '\\', 'u', lowerHex[r>>12&0xF], lowerHex[r>>8&0xF], lowerHex[r>>4&0xF], lowerHex[r&0xF])
+}
@@ -190,3 +190,99 @@ func TestUnescape(t *testing.T) {
 		}
 	}
 }
+
+func TestUnescapeInPlace(t *testing.T) {
+	for _, test := range unescapeTests {
+		in := []byte(test.in)
+
+		out, err := UnescapeInPlace(in)
+		if isErr := (err != nil); isErr != test.isErr {
+			t.Errorf("UnescapeInPlace(`%s`) returned isErr mismatch: expected %t, obtained %t", test.in, test.isErr, isErr)
+		} else if isErr {
+			continue
+		} else if !bytes.Equal(out, []byte(test.out)) {
+			t.Errorf("UnescapeInPlace(`%s`) returned unescaped mismatch: expected `%s`, obtained `%s`", test.in, test.out, out)
+		} else if len(in) > 0 && !isSameMemory(out, in) {
+			t.Errorf("UnescapeInPlace(`%s`) did not write into its input", test.in)
+		}
+	}
+}
+
+type escapeTest struct {
+	in    string      // unescaped string
+	flags EscapeFlags // optional escapes
+	out   string      // expected escaped string, without quotes
+}
+
+var escapeTests = []escapeTest{
+	{in: ``, out: ``},
+	{in: `abcde`, out: `abcde`},
+	{in: `ab"de`, out: `ab\"de`},
+	{in: `ab\de`, out: `ab\\de`},
+	{in: `a/b`, out: `a/b`},
+	{in: "\b\f\n\r\t", out: `\b\f\n\r\t`},
+	{in: "\x00\x01\x1f\x7f", out: `\u0000\u0001\u001f` + "\x7f"},
+	{in: "ab \u00b0 de", out: "ab \u00b0 de"},
+	{in: "ab \U0001F603 de", out: "ab \U0001F603 de"},
+	{in: "a\xffb", out: `a\ufffdb`},
+	{in: "<a & b>", out: "<a & b>"},
+	{in: "\u2028\u2029", out: "\u2028\u2029"},
+
+	{in: "<a & b>", flags: EscapeHTML, out: `\u003ca \u0026 b\u003e`},
+	{in: "ab \u00b0 de", flags: EscapeASCII, out: `ab \u00b0 de`},
+	{in: "\uFFFF", flags: EscapeASCII, out: `\uffff`},
+	{in: "ab \U0001F603 de", flags: EscapeASCII, out: `ab \ud83d\ude03 de`},
+	{in: "\U00010000\U0010FFFF", flags: EscapeASCII, out: `\ud800\udc00\udbff\udfff`},
+	{in: "a\xffb", flags: EscapeASCII, out: `a\ufffdb`},
+	{in: "a\u2028b\u2029c", flags: EscapeLineTerminators, out: `a\u2028b\u2029c`},
+	{in: "\u00b0\u2028", flags: EscapeLineTerminators, out: "\u00b0" + `\u2028`},
+	{in: "<\u00b0\u2028\"", flags: EscapeHTML | EscapeASCII | EscapeLineTerminators, out: `\u003c\u00b0\u2028\"`},
+}
+
+func TestEscape(t *testing.T) {
+	for _, test := range escapeTests {
+		out := EscapeWithFlags(nil, test.in, test.flags)
+		if string(out) != test.out {
+			t.Errorf("EscapeWithFlags(%q, %d) returned mismatch: expected `%s`, obtained `%s`", test.in, test.flags, test.out, out)
+		}
+
+		quoted := AppendQuotedWithFlags([]byte("prefix:"), test.in, test.flags)
+		if string(quoted) != `prefix:"`+test.out+`"` {
+			t.Errorf("AppendQuotedWithFlags(%q, %d) returned mismatch: obtained `%s`", test.in, test.flags, quoted)
+		}
+
+		if test.flags == 0 && string(Escape(nil, test.in)) != test.out {
+			t.Errorf("Escape(%q) should match EscapeWithFlags with no flags", test.in)
+		}
+	}
+}
+
+func TestEscapeRoundTrip(t *testing.T) {
+	inputs := []string{"", "plain", "quote \" backslash \\ slash /", "\x00\x1f\b\f\n\r\t", "\u00b0\uFF11\U0001F603", "<&>\u2028\u2029"}
+	allFlags := []EscapeFlags{0, EscapeHTML, EscapeASCII, EscapeLineTerminators, EscapeHTML | EscapeASCII | EscapeLineTerminators}
+
+	for _, in := range inputs {
+		for _, flags := range allFlags {
+			escaped := EscapeWithFlags(nil, in, flags)
+			if out, err := Unescape(escaped, nil); err != nil || string(out) != in {
+				t.Errorf("Unescape(EscapeWithFlags(%q, %d)) returned %q, %v", in, flags, out, err)
+			}
+			if v, err := ParseString(escaped); err != nil || v != in {
+				t.Errorf("ParseString(EscapeWithFlags(%q, %d)) returned %q, %v", in, flags, v, err)
+			}
+			if validate(AppendQuotedWithFlags(nil, in, flags)) != nil {
+				t.Errorf("AppendQuotedWithFlags(%q, %d) is not a valid JSON string", in, flags)
+			}
+		}
+	}
+}
+
+func TestEscapeNoAllocWithCapacity(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendQuoted(buf[:0], "ab\"c°\n")
+	})
+	if allocs != 0 {
+		t.Errorf("AppendQuoted into a buffer with capacity allocated %v times", allocs)
+	}
+}
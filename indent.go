@@ -0,0 +1,263 @@
+package jsonparser
+
+import (
+	"bytes"
+	"sort"
+)
+
+// IndentOption configures Indent.
+type IndentOption func(*indenter)
+
+// IndentSortedKeys makes Indent write the members of every object sorted by key, comparing keys byte by byte
+// after unescaping them. Members with the same key keep their order.
+func IndentSortedKeys() IndentOption {
+	return func(w *indenter) {
+		w.sortKeys = true
+	}
+}
+
+// IndentInlineWidth makes Indent write objects and arrays on a single line, as in {"a": 1, "b": [2, 3]},
+// when that line is at most width bytes long, not counting the indentation before it.
+func IndentInlineWidth(width int) IndentOption {
+	return func(w *indenter) {
+		w.inlineWidth = width
+	}
+}
+
+// IndentColors makes Indent color its output with the ANSI escape sequences of scheme, for terminals.
+func IndentColors(scheme ColorScheme) IndentOption {
+	return func(w *indenter) {
+		w.colors = scheme
+	}
+}
+
+// ColorScheme holds the ANSI escape sequences that start each kind of token. Empty sequences leave
+// tokens uncolored, so the zero value colors nothing.
+type ColorScheme struct {
+	Key, String, Number, Bool, Null, Punctuation string
+}
+
+// DefaultColors is a ColorScheme readable on both dark and light terminals.
+var DefaultColors = ColorScheme{
+	Key:    "\x1b[34;1m",
+	String: "\x1b[32m",
+	Number: "\x1b[36m",
+	Bool:   "\x1b[33m",
+	Null:   "\x1b[90m",
+}
+
+const colorReset = "\x1b[0m"
+
+// Indent writes src pretty-printed into dst[:0], growing it only if it is too small, and returns the result.
+// Like encoding/json's Indent, every member of an object or array begins on a new line starting with
+// prefix followed by one copy of indent per level of nesting, the first line has no prefix, and empty
+// objects and arrays are written as {} and []. Strings and numbers are written as they appear in src.
+// src must be valid JSON.
+func Indent(dst, src []byte, prefix, indent string, opts ...IndentOption) ([]byte, error) {
+	w := &indenter{out: dst[:0], prefix: prefix, indent: indent}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if err := validate(src); err != nil {
+		return nil, err
+	}
+	start := nextToken(src)
+	_, _, end, err := getType(src, start)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.value(src[start:end], 0); err != nil {
+		return nil, err
+	}
+	return w.out, nil
+}
+
+// indenter accumulates the output of Indent.
+type indenter struct {
+	out            []byte
+	prefix, indent string
+	sortKeys       bool
+	inlineWidth    int
+	colors         ColorScheme
+}
+
+// value writes a single value, found at the start of data, nested depth levels deep.
+func (w *indenter) value(data []byte, depth int) error {
+	if data[0] != '{' && data[0] != '[' {
+		w.scalar(data)
+		return nil
+	}
+
+	spans, err := w.members(data)
+	if err != nil {
+		return err
+	}
+	if len(spans) == 0 {
+		w.punctuation(data[0], closingFor(data[0]))
+		return nil
+	}
+	if w.inlineWidth > 0 && inlineWidth(data, w.inlineWidth) != -1 {
+		return w.inline(data, spans)
+	}
+
+	w.punctuation(data[0])
+	for i, s := range spans {
+		if i > 0 {
+			w.punctuation(',')
+		}
+		w.newline(depth + 1)
+		if data[0] == '{' {
+			w.key(data, s)
+		}
+		if err := w.value(data[s.offset:s.endOffset], depth+1); err != nil {
+			return err
+		}
+	}
+	w.newline(depth)
+	w.punctuation(closingFor(data[0]))
+	return nil
+}
+
+// inline writes an object or array, and everything in it, on a single line.
+func (w *indenter) inline(data []byte, spans []editSpan) error {
+	w.punctuation(data[0])
+	for i, s := range spans {
+		if i > 0 {
+			w.punctuation(',')
+			w.out = append(w.out, ' ')
+		}
+		if data[0] == '{' {
+			w.key(data, s)
+		}
+
+		v := data[s.offset:s.endOffset]
+		if v[0] != '{' && v[0] != '[' {
+			w.scalar(v)
+			continue
+		}
+		inner, err := w.members(v)
+		if err != nil {
+			return err
+		}
+		if len(inner) == 0 {
+			w.punctuation(v[0], closingFor(v[0]))
+		} else if err := w.inline(v, inner); err != nil {
+			return err
+		}
+	}
+	w.punctuation(closingFor(data[0]))
+	return nil
+}
+
+// members returns the members of an object, sorted if the keys are to be sorted, or the elements of an array.
+func (w *indenter) members(data []byte) ([]editSpan, error) {
+	spans, err := containerSpans(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if w.sortKeys && data[0] == '{' {
+		sort.SliceStable(spans, func(i, j int) bool {
+			return bytes.Compare(spans[i].key, spans[j].key) < 0
+		})
+	}
+	return spans, nil
+}
+
+// key writes the key of the member s of the object data as it is written there, followed by a colon.
+func (w *indenter) key(data []byte, s editSpan) {
+	keyEnd, _ := stringEnd(data[s.keyOffset+1:])
+	w.colored(w.colors.Key, data[s.keyOffset:s.keyOffset+1+keyEnd])
+	w.punctuation(':')
+	w.out = append(w.out, ' ')
+}
+
+// scalar writes a string, number, boolean or null.
+func (w *indenter) scalar(data []byte) {
+	color := w.colors.Number
+	switch data[0] {
+	case '"':
+		color = w.colors.String
+	case 't', 'f':
+		color = w.colors.Bool
+	case 'n':
+		color = w.colors.Null
+	}
+	w.colored(color, data)
+}
+
+// punctuation writes braces, brackets, commas and colons.
+func (w *indenter) punctuation(chars ...byte) {
+	w.colored(w.colors.Punctuation, chars)
+}
+
+// colored writes token, wrapped in color and a reset unless color is empty.
+func (w *indenter) colored(color string, token []byte) {
+	if color == "" {
+		w.out = append(w.out, token...)
+		return
+	}
+	w.out = append(w.out, color...)
+	w.out = append(w.out, token...)
+	w.out = append(w.out, colorReset...)
+}
+
+// newline starts a line indented depth levels.
+func (w *indenter) newline(depth int) {
+	w.out = append(w.out, '\n')
+	w.out = append(w.out, w.prefix...)
+	for i := 0; i < depth; i++ {
+		w.out = append(w.out, w.indent...)
+	}
+}
+
+// inlineWidth returns the length of the single-line form of the value data, or -1 if it is longer than limit.
+func inlineWidth(data []byte, limit int) int {
+	if data[0] != '{' && data[0] != '[' {
+		if len(data) > limit {
+			return -1
+		}
+		return len(data)
+	}
+
+	spans, err := containerSpans(data, 0)
+	if err != nil {
+		return -1
+	}
+
+	// The brackets, and a comma and a space between members
+	width := 2
+	if len(spans) > 1 {
+		width += 2 * (len(spans) - 1)
+	}
+	for _, s := range spans {
+		if data[0] == '{' {
+			// The key, a colon and a space
+			keyEnd, _ := stringEnd(data[s.keyOffset+1:])
+			width += keyEnd + 1 + 2
+		}
+		if width > limit {
+			return -1
+		}
+
+		w := inlineWidth(data[s.offset:s.endOffset], limit-width)
+		if w == -1 {
+			return -1
+		}
+		width += w
+	}
+
+	if width > limit {
+		return -1
+	}
+	return width
+}
+
+// closingFor returns the bracket closing the object or array opened by open.
+func closingFor(open byte) byte {
+	if open == '{' {
+		return '}'
+	}
+	return ']'
+}
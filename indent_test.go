@@ -0,0 +1,86 @@
+package jsonparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var indentTests = []struct {
+	desc     string
+	json     string
+	opts     []IndentOption
+	expected string
+	isErr    bool
+}{
+	{
+		desc:     "nested",
+		json:     `{"a":1,"b":[true,null,{"c":"d"}],"e":{},"f":[]}`,
+		expected: "{\n>  \"a\": 1,\n>  \"b\": [\n>    true,\n>    null,\n>    {\n>      \"c\": \"d\"\n>    }\n>  ],\n>  \"e\": {},\n>  \"f\": []\n>}",
+	},
+	{
+		desc:     "scalar",
+		json:     ` "a b" `,
+		expected: `"a b"`,
+	},
+	{
+		desc:     "sorted keys",
+		json:     `{"b":1,"a":{"d":2,"c":3},"\u0041":4}`,
+		opts:     []IndentOption{IndentSortedKeys()},
+		expected: "{\n>  \"\\u0041\": 4,\n>  \"a\": {\n>    \"c\": 3,\n>    \"d\": 2\n>  },\n>  \"b\": 1\n>}",
+	},
+	{
+		desc:     "short containers inline",
+		json:     `{"point":{"x":1,"y":2},"tags":["a","b"],"long":["abcdefghij","abcdefghij"]}`,
+		opts:     []IndentOption{IndentInlineWidth(20)},
+		expected: "{\n>  \"point\": {\"x\": 1, \"y\": 2},\n>  \"tags\": [\"a\", \"b\"],\n>  \"long\": [\n>    \"abcdefghij\",\n>    \"abcdefghij\"\n>  ]\n>}",
+	},
+	{
+		desc:     "whole document inline",
+		json:     `[1,[2,{}],[]]`,
+		opts:     []IndentOption{IndentInlineWidth(20)},
+		expected: `[1, [2, {}], []]`,
+	},
+	{
+		desc:     "colors",
+		json:     `{"a":["s",1,false,null]}`,
+		opts:     []IndentOption{IndentColors(ColorScheme{Key: "K", String: "S", Number: "N", Bool: "B", Null: "U"}), IndentInlineWidth(40)},
+		expected: "{K\"a\"\x1b[0m: [S\"s\"\x1b[0m, N1\x1b[0m, Bfalse\x1b[0m, Unull\x1b[0m]}",
+	},
+	{
+		desc:     "colored punctuation",
+		json:     `[1]`,
+		opts:     []IndentOption{IndentColors(ColorScheme{Punctuation: "P"})},
+		expected: "P[\x1b[0m\n>  1\n>P]\x1b[0m",
+	},
+	{desc: "malformed", json: `{"a":1,}`, isErr: true},
+	{desc: "trailing garbage", json: `[1] 2`, isErr: true},
+}
+
+func TestIndent(t *testing.T) {
+	for _, test := range indentTests {
+		value, err := Indent(nil, []byte(test.json), ">", "  ", test.opts...)
+		if (err != nil) != test.isErr {
+			t.Errorf("Indent test '%s' isErr mismatch: expected %t, obtained %v", test.desc, test.isErr, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("Indent test '%s' expected %q, obtained %q", test.desc, test.expected, value)
+		}
+	}
+}
+
+func TestIndentMatchesEncodingJSON(t *testing.T) {
+	for _, test := range validateTests {
+		if test.isErr {
+			continue
+		}
+
+		// encoding/json keeps trailing whitespace, which Indent drops
+		var expected bytes.Buffer
+		if err := json.Indent(&expected, bytes.TrimRight([]byte(test.json), " \t\r\n"), "//", "\t"); err != nil {
+			t.Fatalf("json.Indent failed on '%s': %v", test.desc, err)
+		}
+		if value, err := Indent(nil, []byte(test.json), "//", "\t"); err != nil || !bytes.Equal(value, expected.Bytes()) {
+			t.Errorf("Indent test '%s' returned %q, %v; expected %q", test.desc, value, err, expected.Bytes())
+		}
+	}
+}
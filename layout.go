@@ -0,0 +1,215 @@
+package jsonparser
+
+import (
+	"bytes"
+)
+
+// layout describes how the members of an object or array are laid out, so that new ones can be written
+// the same way.
+type layout struct {
+	// sep comes between a comma and the next member, such as "\n    " or " "
+	sep []byte
+	// newline and unit are the line break and one level of indentation, both empty on a single line
+	newline, unit []byte
+	// indent is the indentation of the members
+	indent []byte
+	// colon separates keys from values, with any whitespace around it
+	colon []byte
+}
+
+// setIndented is the part of Set that adds new members to an existing object or array when
+// PreserveIndentation is set: new members go on their own lines, indented like their siblings.
+// It returns false when the path needs the plain insertion of Set, such as when a value on the
+// path is replaced.
+func (p *Parser) setIndented(dst, data []byte, setValue []byte, keys ...string) (value []byte, ok bool, err error) {
+	// Find the deepest value on the path that exists
+	_, _, start, end, err := p.internalGet(data)
+	if err != nil {
+		return nil, false, nil
+	}
+	depth := 0
+	for ; depth < len(keys); depth++ {
+		_, _, s, e, err := p.internalGet(data, keys[:depth+1]...)
+		if err != nil {
+			break
+		}
+		start, end = s, e
+	}
+
+	isIndex, idx := isValidArrayIndex(keys[depth])
+	switch {
+	case data[start] == '{' && !isIndex:
+	case data[start] == '[' && isIndex && depth > 0:
+	default:
+		return nil, false, nil
+	}
+
+	spans, err := containerSpans(data, start)
+	if err != nil {
+		return nil, false, err
+	}
+	l, ok := detectLayout(data, start, spans)
+	if !ok {
+		return nil, false, nil
+	}
+
+	// The new member, or the new element with the null padding before it
+	var member []byte
+	if data[start] == '{' {
+		member = AppendQuoted(member, segmentName(keys[depth]))
+		member = append(member, l.colon...)
+	} else if keys[depth] != Append() && keys[depth] != Prepend() {
+		for i := len(spans); i < idx; i++ {
+			member = append(member, nullLiteral...)
+			member = append(member, ',')
+			member = append(member, l.sep...)
+		}
+	}
+	member = l.appendNew(member, keys[depth+1:], setValue, l.indent)
+
+	switch {
+	case len(spans) == 0:
+		containerIndent := lineIndent(data, start)
+		return appendSpan(dst, data, start+1, end-1, l.newline, l.indent, member, l.newline, containerIndent), true, nil
+	case keys[depth] == Prepend():
+		first := spans[0].keyOffset
+		return appendSpan(dst, data, first, first, member, []byte{','}, data[start+1:first]), true, nil
+	default:
+		last := spans[len(spans)-1].endOffset
+		return appendSpan(dst, data, last, last, []byte{','}, l.sep, member), true, nil
+	}
+}
+
+// appendNew appends setValue nested in the objects and arrays that keys create, indenting each level by one unit.
+func (l *layout) appendNew(out []byte, keys []string, setValue []byte, indent []byte) []byte {
+	if len(keys) == 0 {
+		return append(out, setValue...)
+	}
+
+	inner := append(append([]byte(nil), indent...), l.unit...)
+	isIndex, idx := isValidArrayIndex(keys[0])
+	if isIndex {
+		out = append(out, '[')
+		for i := 0; i < idx; i++ {
+			out = append(out, l.newline...)
+			out = append(out, inner...)
+			out = append(out, nullLiteral...)
+			out = append(out, ',')
+		}
+	} else {
+		out = append(out, '{')
+	}
+	out = append(out, l.newline...)
+	out = append(out, inner...)
+	if !isIndex {
+		out = AppendQuoted(out, segmentName(keys[0]))
+		out = append(out, l.colon...)
+	}
+
+	out = l.appendNew(out, keys[1:], setValue, inner)
+
+	out = append(out, l.newline...)
+	out = append(out, indent...)
+	if isIndex {
+		return append(out, ']')
+	}
+	return append(out, '}')
+}
+
+// detectLayout works out the layout of the object or array starting at data[start] from its last
+// member, or from the rest of the document when it is empty. It returns false for empty objects and
+// arrays of documents that are not indented, which are best filled by the plain insertion of Set.
+func detectLayout(data []byte, start int, spans []editSpan) (l layout, ok bool) {
+	containerIndent := lineIndent(data, start)
+
+	if len(spans) == 0 {
+		if l.unit = indentUnit(data); l.unit == nil {
+			return l, false
+		}
+		l.newline = []byte{'\n'}
+		if i := bytes.IndexByte(data, '\n'); i > 0 && data[i-1] == '\r' {
+			l.newline = []byte{'\r', '\n'}
+		}
+		l.indent = append(append([]byte(nil), containerIndent...), l.unit...)
+		l.sep = append(append([]byte(nil), l.newline...), l.indent...)
+		l.colon = documentColon(data)
+		return l, true
+	}
+
+	// The whitespace between the last member and the comma or bracket before it
+	last := spans[len(spans)-1]
+	from := start + 1
+	if len(spans) > 1 {
+		from = spans[len(spans)-2].endOffset
+	}
+	l.sep = data[from:last.keyOffset]
+	if i := bytes.LastIndexByte(l.sep, ','); i != -1 {
+		l.sep = l.sep[i+1:]
+	}
+
+	if i := bytes.LastIndexByte(l.sep, '\n'); i != -1 {
+		l.newline, l.indent = l.sep[:i+1], l.sep[i+1:]
+		if j := bytes.LastIndexByte(l.newline[:i], '\n'); j != -1 {
+			// Keep a single line break of blank lines
+			l.newline = l.newline[j+1:]
+		}
+		if bytes.HasPrefix(l.indent, containerIndent) && len(l.indent) > len(containerIndent) {
+			l.unit = l.indent[len(containerIndent):]
+		} else {
+			l.unit = indentUnit(data)
+		}
+	}
+
+	if data[start] == '{' {
+		keyEnd, _ := stringEnd(data[last.keyOffset+1:])
+		l.colon = data[last.keyOffset+1+keyEnd : last.offset]
+	} else {
+		l.colon = documentColon(data)
+	}
+	return l, true
+}
+
+// lineIndent returns the whitespace at the start of the line holding data[pos].
+func lineIndent(data []byte, pos int) []byte {
+	from := bytes.LastIndexByte(data[:pos], '\n') + 1
+	to := from
+	for to < pos && (data[to] == ' ' || data[to] == '\t') {
+		to++
+	}
+	return data[from:to]
+}
+
+// indentUnit returns the indentation of the first indented line of data, which in a document starting at
+// the beginning of a line is one level, or nil if no line is indented. JSON strings cannot hold raw line
+// breaks, so every line break is whitespace between tokens.
+func indentUnit(data []byte) []byte {
+	for i := bytes.IndexByte(data, '\n'); i != -1; {
+		to := i + 1
+		for to < len(data) && (data[to] == ' ' || data[to] == '\t') {
+			to++
+		}
+		if to > i+1 && to < len(data) && data[to] != '\r' && data[to] != '\n' {
+			return data[i+1 : to]
+		}
+
+		next := bytes.IndexByte(data[i+1:], '\n')
+		if next == -1 {
+			break
+		}
+		i += next + 1
+	}
+	return nil
+}
+
+// documentColon returns how the first member of the top-level object separates its key from its value,
+// falling back to ": " as encoding/json indents.
+func documentColon(data []byte) []byte {
+	start := nextToken(data)
+	if start != -1 && data[start] == '{' {
+		if spans, err := containerSpans(data, start); err == nil && len(spans) > 0 {
+			keyEnd, _ := stringEnd(data[spans[0].keyOffset+1:])
+			return data[spans[0].keyOffset+1+keyEnd : spans[0].offset]
+		}
+	}
+	return []byte(": ")
+}
@@ -0,0 +1,132 @@
+package jsonparser
+
+import (
+	"errors"
+)
+
+// ErrLimitExceeded is returned when a document, or a value being written into it, exceeds the parser's Limits.
+var ErrLimitExceeded = errors.New("Document exceeds configured limits")
+
+// Limits bounds the resources a single document may use, which matters when parsing untrusted input.
+// A zero field is not enforced, so the zero value imposes no limits at all.
+// Its Get, EachKey, Set, Delete, ArrayEach and ObjectEach methods are shorthand for those of a Parser
+// using only the limits.
+type Limits struct {
+	// MaxDepth is the deepest nesting of objects and arrays allowed; a scalar document has depth 0.
+	MaxDepth int
+	// MaxDocumentSize is the largest document allowed, in bytes.
+	MaxDocumentSize int
+	// MaxStringLength is the longest string (keys included) allowed, in bytes as they appear in the document.
+	MaxStringLength int
+	// MaxArrayElements is the largest number of elements allowed in a single array.
+	MaxArrayElements int
+	// MaxObjectKeys is the largest number of keys allowed in a single object.
+	MaxObjectKeys int
+}
+
+// How many levels of nesting check can track without allocating
+const limitsStackBufSize = 32
+
+type limitsContainer struct {
+	array bool
+	count int
+}
+
+// check scans data once and returns ErrLimitExceeded if it breaks any of the limits.
+// It does not validate the document; malformed input is left for the parsing functions to report.
+func (l *Limits) check(data []byte) error {
+	if l.MaxDocumentSize > 0 && len(data) > l.MaxDocumentSize {
+		return ErrLimitExceeded
+	}
+	if l.MaxDepth <= 0 && l.MaxStringLength <= 0 && l.MaxArrayElements <= 0 && l.MaxObjectKeys <= 0 {
+		return nil
+	}
+
+	var stackbuf [limitsStackBufSize]limitsContainer // stack-allocated array for allocation-free checking of shallow documents
+	stack := stackbuf[:0]
+
+	// Whether the next token starts a new element of the innermost array
+	pendingElement := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch c {
+		case ' ', '\n', '\r', '\t':
+			continue
+		}
+
+		if pendingElement && c != ']' {
+			pendingElement = false
+			top := &stack[len(stack)-1]
+			if top.count++; l.MaxArrayElements > 0 && top.count > l.MaxArrayElements {
+				return ErrLimitExceeded
+			}
+		}
+
+		switch c {
+		case '"':
+			strEnd, _ := stringEnd(data[i+1:])
+			if strEnd == -1 {
+				return nil
+			}
+			if l.MaxStringLength > 0 && strEnd-1 > l.MaxStringLength {
+				return ErrLimitExceeded
+			}
+			i += strEnd
+		case '{', '[':
+			if l.MaxDepth > 0 && len(stack) >= l.MaxDepth {
+				return ErrLimitExceeded
+			}
+			stack = append(stack, limitsContainer{array: c == '['})
+			pendingElement = c == '['
+		case '}', ']':
+			if len(stack) == 0 {
+				return nil
+			}
+			stack = stack[:len(stack)-1]
+			pendingElement = false
+		case ',':
+			pendingElement = len(stack) > 0 && stack[len(stack)-1].array
+		case ':':
+			if len(stack) > 0 && !stack[len(stack)-1].array {
+				top := &stack[len(stack)-1]
+				if top.count++; l.MaxObjectKeys > 0 && top.count > l.MaxObjectKeys {
+					return ErrLimitExceeded
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Get behaves like the package-level Get, failing with ErrLimitExceeded if data breaks the limits.
+func (l Limits) Get(data []byte, keys ...string) (value []byte, dataType ValueType, offset int, err error) {
+	return (&Parser{Limits: l}).Get(data, keys...)
+}
+
+// EachKey behaves like the package-level EachKey. If data breaks the limits, cb is called once with
+// index -1 and ErrLimitExceeded.
+func (l Limits) EachKey(data []byte, cb func(int, []byte, ValueType, error), paths ...[]string) int {
+	return (&Parser{Limits: l}).EachKey(data, cb, paths...)
+}
+
+// Set behaves like the package-level Set, failing with ErrLimitExceeded if data or the result break the limits.
+func (l Limits) Set(data []byte, setValue []byte, keys ...string) (value []byte, err error) {
+	return (&Parser{Limits: l}).Set(data, setValue, keys...)
+}
+
+// Delete behaves like the package-level Delete. Documents breaking the limits are returned unchanged.
+func (l Limits) Delete(data []byte, keys ...string) []byte {
+	return (&Parser{Limits: l}).Delete(data, keys...)
+}
+
+// ArrayEach behaves like the package-level ArrayEach, failing with ErrLimitExceeded if data breaks the limits.
+func (l Limits) ArrayEach(data []byte, cb func(value []byte, dataType ValueType, offset int, err error), keys ...string) (offset int, err error) {
+	return (&Parser{Limits: l}).ArrayEach(data, cb, keys...)
+}
+
+// ObjectEach behaves like the package-level ObjectEach, failing with ErrLimitExceeded if data breaks the limits.
+func (l Limits) ObjectEach(data []byte, callback func(key []byte, value []byte, dataType ValueType, offset int) error, keys ...string) (err error) {
+	return (&Parser{Limits: l}).ObjectEach(data, callback, keys...)
+}
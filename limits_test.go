@@ -0,0 +1,322 @@
+package jsonparser
+
+import (
+	"strings"
+	"testing"
+)
+
+type LimitsTest struct {
+	desc   string
+	json   string
+	limits Limits
+
+	isErr bool
+}
+
+var limitsTests = []LimitsTest{
+	{
+		desc:   "no limits",
+		json:   `{"a":[[[[[1]]]]]}`,
+		limits: Limits{},
+	},
+	{
+		desc:   "depth within limit",
+		json:   `{"a":[[1]]}`,
+		limits: Limits{MaxDepth: 3},
+	},
+	{
+		desc:   "depth exceeded",
+		json:   `{"a":[[[1]]]}`,
+		limits: Limits{MaxDepth: 3},
+		isErr:  true,
+	},
+	{
+		desc:   "depth exceeded by brackets in strings is not counted",
+		json:   `{"a":"[[[[{{{{"}`,
+		limits: Limits{MaxDepth: 1},
+	},
+	{
+		desc:   "depth of scalar",
+		json:   `"a"`,
+		limits: Limits{MaxDepth: 1},
+	},
+	{
+		desc:   "document size within limit",
+		json:   `{"a":1}`,
+		limits: Limits{MaxDocumentSize: 7},
+	},
+	{
+		desc:   "document size exceeded",
+		json:   `{"a":12}`,
+		limits: Limits{MaxDocumentSize: 7},
+		isErr:  true,
+	},
+	{
+		desc:   "string length within limit",
+		json:   `{"abc":"def"}`,
+		limits: Limits{MaxStringLength: 3},
+	},
+	{
+		desc:   "string length exceeded by value",
+		json:   `{"abc":"defg"}`,
+		limits: Limits{MaxStringLength: 3},
+		isErr:  true,
+	},
+	{
+		desc:   "string length exceeded by key",
+		json:   `{"abcd":"def"}`,
+		limits: Limits{MaxStringLength: 3},
+		isErr:  true,
+	},
+	{
+		desc:   "string length counts escapes as they appear",
+		json:   `["\"\""]`,
+		limits: Limits{MaxStringLength: 3},
+		isErr:  true,
+	},
+	{
+		desc:   "array elements within limit",
+		json:   `[1, [2, 3, 4], "a,b,c"]`,
+		limits: Limits{MaxArrayElements: 3},
+	},
+	{
+		desc:   "array elements exceeded",
+		json:   `[1, 2, 3, 4]`,
+		limits: Limits{MaxArrayElements: 3},
+		isErr:  true,
+	},
+	{
+		desc:   "nested array elements exceeded",
+		json:   `[[1, 2, 3, 4]]`,
+		limits: Limits{MaxArrayElements: 3},
+		isErr:  true,
+	},
+	{
+		desc:   "empty arrays have no elements",
+		json:   `[[ ], []]`,
+		limits: Limits{MaxArrayElements: 2},
+	},
+	{
+		desc:   "object keys within limit",
+		json:   `{"a":{"x":1,"y":2},"b":"c:d","c":[{"z":3}]}`,
+		limits: Limits{MaxObjectKeys: 3},
+	},
+	{
+		desc:   "object keys exceeded",
+		json:   `{"a":1,"b":2,"c":3,"d":4}`,
+		limits: Limits{MaxObjectKeys: 3},
+		isErr:  true,
+	},
+	{
+		desc:   "nested object keys exceeded",
+		json:   `[{"a":1,"b":2}]`,
+		limits: Limits{MaxObjectKeys: 1},
+		isErr:  true,
+	},
+	{
+		desc:   "malformed input is left to the parser",
+		json:   `{"a":"unterminated`,
+		limits: Limits{MaxStringLength: 100},
+	},
+}
+
+func TestLimitsCheck(t *testing.T) {
+	for _, test := range limitsTests {
+		if activeTest != "" && test.desc != activeTest {
+			continue
+		}
+
+		err := test.limits.check([]byte(test.json))
+		if isErr := (err != nil); test.isErr != isErr {
+			t.Errorf("Limits test '%s' isErr mismatch: expected %t, obtained %t (err %v)", test.desc, test.isErr, isErr, err)
+		} else if isErr && err != ErrLimitExceeded {
+			t.Errorf("Limits test '%s' expected ErrLimitExceeded, obtained %v", test.desc, err)
+		}
+	}
+}
+
+func TestLimitsDeepNesting(t *testing.T) {
+	data := []byte(strings.Repeat("[", 100000) + strings.Repeat("]", 100000))
+	l := Limits{MaxDepth: 64}
+
+	if _, _, _, err := l.Get(data, "[0]", "[0]"); err != ErrLimitExceeded {
+		t.Errorf("Get expected ErrLimitExceeded, obtained %v", err)
+	}
+	if _, err := l.ArrayEach(data, func([]byte, ValueType, int, error) {}); err != ErrLimitExceeded {
+		t.Errorf("ArrayEach expected ErrLimitExceeded, obtained %v", err)
+	}
+}
+
+func TestLimitsMethods(t *testing.T) {
+	l := Limits{MaxDepth: 2, MaxObjectKeys: 2}
+	data := []byte(`{"a":{"b":1},"c":[1,2]}`)
+
+	if v, _, _, err := l.Get(data, "a", "b"); err != nil || string(v) != "1" {
+		t.Errorf("Get within limits returned %s, %v", v, err)
+	}
+
+	if err := l.ObjectEach([]byte(`{"a":1,"b":2,"c":3}`), func([]byte, []byte, ValueType, int) error { return nil }); err != ErrLimitExceeded {
+		t.Errorf("ObjectEach expected ErrLimitExceeded, obtained %v", err)
+	}
+
+	var eachKeyErr error
+	l.EachKey([]byte(`[[[1]]]`), func(idx int, value []byte, vt ValueType, err error) {
+		eachKeyErr = err
+	}, []string{"[0]"})
+	if eachKeyErr != ErrLimitExceeded {
+		t.Errorf("EachKey expected ErrLimitExceeded, obtained %v", eachKeyErr)
+	}
+
+	// Set must not produce a document deeper than allowed
+	if _, err := l.Set(data, []byte(`1`), "a", "x", "y"); err != ErrLimitExceeded {
+		t.Errorf("Set creating nested objects expected ErrLimitExceeded, obtained %v", err)
+	}
+	if _, err := l.Set(data, []byte(`{"x":{}}`), "a", "b"); err != ErrLimitExceeded {
+		t.Errorf("Set of nested value expected ErrLimitExceeded, obtained %v", err)
+	}
+	if _, err := l.Set(data, []byte(`3`), "d"); err != ErrLimitExceeded {
+		t.Errorf("Set of extra key expected ErrLimitExceeded, obtained %v", err)
+	}
+	if value, err := l.Set(data, []byte(`3`), "c", "[+]"); err != nil || string(value) != `{"a":{"b":1},"c":[1,2,3]}` {
+		t.Errorf("Set within limits returned %s, %v", value, err)
+	}
+
+	if value, err := (&Parser{Limits: l}).AppendSet(make([]byte, 0, 64), data, []byte(`3`), "d"); err != ErrLimitExceeded {
+		t.Errorf("AppendSet of extra key expected ErrLimitExceeded, obtained %s, %v", value, err)
+	}
+
+	deep := []byte(`{"a":[[1]]}`)
+	if value := l.Delete(deep, "a"); string(value) != string(deep) {
+		t.Errorf("Delete on document over limits should return it unchanged, obtained %s", value)
+	}
+}
+
+func TestParserTypedGettersLimits(t *testing.T) {
+	p := &Parser{Limits: Limits{MaxDepth: 1}}
+	data := []byte(`{"s":"a\nb","i":-3,"u":4,"f":1.5,"b":true,"n":"7"}`)
+	deep := []byte(`{"s":"x","i":1,"u":1,"f":1,"b":true,"n":"1","d":[[]]}`)
+
+	checks := []struct {
+		name string
+		get  func(data []byte) (interface{}, error)
+		want interface{}
+	}{
+		{"GetString", func(d []byte) (interface{}, error) { return p.GetString(d, "s") }, "a\nb"},
+		{"GetUnsafeString", func(d []byte) (interface{}, error) { return p.GetUnsafeString(d, "s") }, `a\nb`},
+		{"GetStringView", func(d []byte) (interface{}, error) { return p.GetStringView(d, nil, "s") }, "a\nb"},
+		{"GetInt", func(d []byte) (interface{}, error) { return p.GetInt(d, "i") }, int64(-3)},
+		{"GetInt32", func(d []byte) (interface{}, error) { return p.GetInt32(d, "i") }, int32(-3)},
+		{"GetUint64", func(d []byte) (interface{}, error) { return p.GetUint64(d, "u") }, uint64(4)},
+		{"GetUint32", func(d []byte) (interface{}, error) { return p.GetUint32(d, "u") }, uint32(4)},
+		{"GetFloat", func(d []byte) (interface{}, error) { return p.GetFloat(d, "f") }, 1.5},
+		{"GetBoolean", func(d []byte) (interface{}, error) { return p.GetBoolean(d, "b") }, true},
+		{"GetNumber", func(d []byte) (interface{}, error) { return p.GetNumber(d, "f") }, RawNumber("1.5")},
+		{"GetBigInt", func(d []byte) (interface{}, error) {
+			v, err := p.GetBigInt(d, "i")
+			if err != nil {
+				return nil, err
+			}
+			return v.Int64(), nil
+		}, int64(-3)},
+		{"GetBigFloat", func(d []byte) (interface{}, error) {
+			v, err := p.GetBigFloat(d, "f")
+			if err != nil {
+				return nil, err
+			}
+			f, _ := v.Float64()
+			return f, nil
+		}, 1.5},
+		{"GetDecimal", func(d []byte) (interface{}, error) { return p.GetDecimal(d, 2, "f") }, int64(150)},
+		{"GetIntCoerce", func(d []byte) (interface{}, error) { return p.GetIntCoerce(d, "n") }, int64(7)},
+		{"GetFloatCoerce", func(d []byte) (interface{}, error) { return p.GetFloatCoerce(d, "n") }, 7.0},
+		{"GetBoolCoerce", func(d []byte) (interface{}, error) { return p.GetBoolCoerce(d, "b") }, true},
+		{"GetStringCoerce", func(d []byte) (interface{}, error) { return p.GetStringCoerce(d, "i") }, "-3"},
+	}
+	for _, c := range checks {
+		if v, err := c.get(data); err != nil || v != c.want {
+			t.Errorf("%s within limits returned %v, %v; expected %v", c.name, v, err, c.want)
+		}
+		if _, err := c.get(deep); err != ErrLimitExceeded {
+			t.Errorf("%s expected ErrLimitExceeded, obtained %v", c.name, err)
+		}
+	}
+
+	// Type mismatches are reported as by the package-level getters
+	if _, err := p.GetInt(data, "s"); err == nil {
+		t.Errorf("GetInt of a string should fail")
+	}
+	if _, err := (&Parser{FoldKeys: true}).GetUint32([]byte(`{"N":5000000000}`), "n"); err != OverflowIntegerError {
+		t.Errorf("GetUint32 expected OverflowIntegerError, obtained %v", err)
+	}
+}
+
+func TestParserMethodsLimits(t *testing.T) {
+	p := &Parser{Limits: Limits{MaxDepth: 2}}
+	data := []byte(`{"a":[1,2],"b":{"c":1}}`)
+	deep := []byte(`{"a":[1,2],"b":{"c":[]}}`)
+
+	checks := []struct {
+		name string
+		call func(data []byte) ([]byte, error)
+		want string
+	}{
+		{"Insert", func(d []byte) ([]byte, error) { return p.Insert(d, []byte(`0`), "a", "[0]") }, `{"a":[0,1,2],"b":{"c":1}}`},
+		{"ArrayRemove", func(d []byte) ([]byte, error) { return p.ArrayRemove(d, 0, 1, "a") }, `{"a":[2],"b":{"c":1}}`},
+		{"ArrayAppend", func(d []byte) ([]byte, error) { return p.ArrayAppend(d, []string{"a"}, []byte(`3`)) }, `{"a":[1,2,3],"b":{"c":1}}`},
+		{"Rename", func(d []byte) ([]byte, error) { return p.Rename(d, "d", "b") }, `{"a":[1,2],"d":{"c":1}}`},
+		{"Move", func(d []byte) ([]byte, error) { return p.Move(d, []string{"b", "c"}, []string{"c"}) }, `{"a":[1,2],"b":{},"c":1}`},
+		{"Edit", func(d []byte) ([]byte, error) { return p.Edit(d).Delete("a").Apply() }, `{"b":{"c":1}}`},
+		{"DeleteMany", func(d []byte) ([]byte, error) { return p.DeleteMany(d, []string{"a"}) }, `{"b":{"c":1}}`},
+		{"DeleteE", func(d []byte) ([]byte, error) {
+			v, _, err := p.DeleteE(d, "a")
+			return v, err
+		}, `{"b":{"c":1}}`},
+		{"ApplyPatch", func(d []byte) ([]byte, error) {
+			return p.ApplyPatch(d, []byte(`[{"op":"remove","path":"/a"}]`))
+		}, `{"b":{"c":1}}`},
+		{"MergePatch", func(d []byte) ([]byte, error) { return p.MergePatch(d, []byte(`{"a":null}`)) }, `{"b":{"c":1}}`},
+		{"Diff", func(d []byte) ([]byte, error) { return p.Diff(d, d) }, `[]`},
+		{"Compact", func(d []byte) ([]byte, error) { return p.Compact(nil, d) }, string(data)},
+		{"Indent", func(d []byte) ([]byte, error) { return p.Indent(nil, d, "", "") }, "{\n\"a\": [\n1,\n2\n],\n\"b\": {\n\"c\": 1\n}\n}"},
+		{"SetString", func(d []byte) ([]byte, error) { return p.SetString(d, "x", "b", "c") }, `{"a":[1,2],"b":{"c":"x"}}`},
+	}
+	for _, c := range checks {
+		if v, err := c.call(data); err != nil || string(v) != c.want {
+			t.Errorf("%s within limits returned %s, %v; expected %s", c.name, v, err, c.want)
+		}
+		if _, err := c.call(deep); err != ErrLimitExceeded {
+			t.Errorf("%s expected ErrLimitExceeded, obtained %v", c.name, err)
+		}
+	}
+
+	if _, err := p.FindDuplicateKeys(deep); err != ErrLimitExceeded {
+		t.Errorf("FindDuplicateKeys expected ErrLimitExceeded, obtained %v", err)
+	}
+	if v := p.AppendDelete(nil, deep, "a"); string(v) != string(deep) {
+		t.Errorf("AppendDelete on document over limits should copy it unchanged, obtained %s", v)
+	}
+	if v := p.DeleteInPlace(append([]byte(nil), deep...), "a"); string(v) != string(deep) {
+		t.Errorf("DeleteInPlace on document over limits should return it unchanged, obtained %s", v)
+	}
+
+	// Results that would break the limits fail as well
+	results := []struct {
+		name string
+		call func() ([]byte, error)
+	}{
+		{"Insert", func() ([]byte, error) { return p.Insert(data, []byte(`[]`), "a", "[0]") }},
+		{"ArrayAppend", func() ([]byte, error) { return p.ArrayAppend(data, []string{"b", "d"}, []byte(`1`)) }},
+		{"Move", func() ([]byte, error) { return p.Move(data, []string{"a"}, []string{"b", "a"}) }},
+		{"Edit", func() ([]byte, error) { return p.Edit(data).Set([]byte(`1`), "b", "c", "d").Apply() }},
+		{"ApplyPatch", func() ([]byte, error) {
+			return p.ApplyPatch(data, []byte(`[{"op":"add","path":"/b/d","value":[]}]`))
+		}},
+		{"MergePatch", func() ([]byte, error) { return p.MergePatch(data, []byte(`{"b":{"d":[]}}`)) }},
+		{"SetInt", func() ([]byte, error) { return p.SetInt(data, 1, "b", "c", "d") }},
+	}
+	for _, r := range results {
+		if v, err := r.call(); err != ErrLimitExceeded {
+			t.Errorf("%s producing a document over limits expected ErrLimitExceeded, obtained %s, %v", r.name, v, err)
+		}
+	}
+}
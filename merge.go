@@ -0,0 +1,51 @@
+package jsonparser
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to target and returns the result: objects in the
+// patch are merged into the target recursively, null members delete keys, and any other value,
+// arrays included, replaces what is in the target. Members keep their order in the target, and new
+// ones are added at the end. target is never modified.
+func MergePatch(target, patch []byte) ([]byte, error) {
+	patchValue, patchType, _, err := Get(patch)
+	if err != nil {
+		return nil, err
+	}
+	if patchType != Object {
+		return rawValue(patch)
+	}
+
+	if _, targetType, _, err := Get(target); err != nil || targetType != Object {
+		target = []byte("{}")
+	}
+
+	err = ObjectEach(patchValue, func(key []byte, value []byte, dataType ValueType, offset int) error {
+		name := Key(string(key))
+
+		if dataType == String {
+			// ObjectEach strips the quotes of strings; offset is where the value ends
+			value = patchValue[offset-len(value)-2 : offset]
+		}
+
+		switch dataType {
+		case Null:
+			target = Delete(target, name)
+			return nil
+		case Object:
+			current, err := rawValue(target, name)
+			if err != nil && err != KeyPathNotFoundError {
+				return err
+			}
+			if value, err = MergePatch(current, value); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		target, err = Set(target, value, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
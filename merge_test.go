@@ -0,0 +1,51 @@
+package jsonparser
+
+import (
+	"testing"
+)
+
+// Cases follow the examples of RFC 7386, Appendix A
+var mergePatchTests = []struct {
+	target   string
+	patch    string
+	expected string
+	isErr    bool
+}{
+	{target: `{"a":"b"}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+	{target: `{"a":"b"}`, patch: `{"b":"c"}`, expected: `{"a":"b","b":"c"}`},
+	{target: `{"a":"b"}`, patch: `{"a":null}`, expected: `{}`},
+	{target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, expected: `{"b":"c"}`},
+	{target: `{"a":["b"]}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+	{target: `{"a":"c"}`, patch: `{"a":["b"]}`, expected: `{"a":["b"]}`},
+	{target: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, expected: `{"a":{"b":"d"}}`},
+	{target: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, expected: `{"a":[1]}`},
+	{target: `["a","b"]`, patch: `["c","d"]`, expected: `["c","d"]`},
+	{target: `{"a":"b"}`, patch: `["c"]`, expected: `["c"]`},
+	{target: `{"a":"foo"}`, patch: `null`, expected: `null`},
+	{target: `{"a":"foo"}`, patch: `"bar"`, expected: `"bar"`},
+	{target: `{"e":null}`, patch: `{"a":1}`, expected: `{"e":null,"a":1}`},
+	{target: `[1,2]`, patch: `{"a":"b","c":null}`, expected: `{"a":"b"}`},
+	{target: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, expected: `{"a":{"bb":{}}}`},
+
+	// Target key order is kept, and escaped keys are matched unescaped
+	{target: `{"x":1,"y":{"z":2},"w":3}`, patch: `{"w":4,"y":{"z":5,"v":6},"x":null}`, expected: `{"y":{"z":5,"v":6},"w":4}`},
+	{target: `{"\u0061":1}`, patch: `{"a":2}`, expected: `{"\u0061":2}`},
+	{target: `{"[0]":1}`, patch: `{"[0]":2}`, expected: `{"[0]":2}`},
+	{target: `{"a":"b"}`, patch: `{"a":"c`, isErr: true},
+}
+
+func TestMergePatch(t *testing.T) {
+	for _, test := range mergePatchTests {
+		target, patch := []byte(test.target), []byte(test.patch)
+		value, err := MergePatch(target, patch)
+
+		if (err != nil) != test.isErr {
+			t.Errorf("MergePatch(%s, %s) isErr mismatch: expected %t, obtained %v", test.target, test.patch, test.isErr, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("MergePatch(%s, %s) expected %s, obtained %s", test.target, test.patch, test.expected, value)
+		}
+		if string(target) != test.target || string(patch) != test.patch {
+			t.Errorf("MergePatch(%s, %s) modified its input: %s, %s", test.target, test.patch, target, patch)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package jsonparser
+
+// Rename gives the object member at keys the name newKey, rewriting only its key and leaving its value
+// and position as they are. The last key must name an object member; renaming to the name of another
+// member of the same object returns DuplicateKeyError. data is never modified.
+func Rename(data []byte, newKey string, keys ...string) ([]byte, error) {
+	return defaultParser.rename(data, newKey, keys...)
+}
+
+// rename implements Rename.
+func (p *Parser) rename(data []byte, newKey string, keys ...string) ([]byte, error) {
+	if len(keys) == 0 {
+		return nil, KeyPathNotFoundError
+	}
+
+	keyOffset, _, _, err := p.lookup(data, keys)
+	if err != nil {
+		return nil, err
+	}
+	if keyOffset == -1 {
+		// Array elements have no key to rename
+		return nil, KeyPathNotFoundError
+	}
+
+	last := len(keys) - 1
+	if newKey == segmentName(keys[last]) {
+		return data, nil
+	}
+
+	sibling := append(append([]string(nil), keys[:last]...), Key(newKey))
+	if _, _, _, _, err := p.internalGet(data, sibling...); err != KeyPathNotFoundError {
+		if err == nil {
+			return nil, DuplicateKeyError
+		}
+		return nil, err
+	}
+
+	keyEnd, _ := stringEnd(data[keyOffset+1:])
+	return replaceSpan(data, keyOffset, keyOffset+1+keyEnd, AppendQuoted(nil, newKey)), nil
+}
+
+// Move removes the value at from and sets it at to, creating missing objects and arrays like Set.
+// Both paths are resolved against the original document, and the result is written in one pass.
+// The value at from must exist, and a path that continues the other returns EditConflictError.
+// data is never modified.
+func Move(data []byte, from, to []string) ([]byte, error) {
+	return defaultParser.move(data, from, to)
+}
+
+// move implements Move with an Editor of p.
+func (p *Parser) move(data []byte, from, to []string) ([]byte, error) {
+	if len(from) == 0 || len(to) == 0 {
+		return nil, KeyPathNotFoundError
+	}
+
+	_, _, offset, endOffset, err := internalGet(data, from...)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Edit(data).Delete(from...).Set(data[offset:endOffset], to...).Apply()
+}
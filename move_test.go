@@ -0,0 +1,161 @@
+package jsonparser
+
+import (
+	"testing"
+)
+
+var renameTests = []struct {
+	desc     string
+	json     string
+	newKey   string
+	path     []string
+	expected string
+	err      error
+}{
+	{
+		desc:     "top-level key",
+		json:     `{"user_name": "bob", "age": 3}`,
+		newKey:   "username",
+		path:     []string{"user_name"},
+		expected: `{"username": "bob", "age": 3}`,
+	},
+	{
+		desc:     "nested key keeps its value and position",
+		json:     `{"a":[{"x":{"y":1},"z":2}]}`,
+		newKey:   "w",
+		path:     []string{"a", "[0]", "x"},
+		expected: `{"a":[{"w":{"y":1},"z":2}]}`,
+	},
+	{
+		desc:     "escaped keys",
+		json:     `{"a":1}`,
+		newKey:   "b\"c",
+		path:     []string{"a"},
+		expected: `{"b\"c":1}`,
+	},
+	{
+		desc:     "same name",
+		json:     `{"a":1}`,
+		newKey:   "a",
+		path:     []string{"a"},
+		expected: `{"a":1}`,
+	},
+	{
+		desc:   "name taken by a sibling",
+		json:   `{"a":1,"b":2}`,
+		newKey: "b",
+		path:   []string{"a"},
+		err:    DuplicateKeyError,
+	},
+	{
+		desc:     "name taken elsewhere",
+		json:     `{"a":1,"c":{"b":2}}`,
+		newKey:   "b",
+		path:     []string{"a"},
+		expected: `{"b":1,"c":{"b":2}}`,
+	},
+	{
+		desc:   "array element",
+		json:   `{"a":[1]}`,
+		newKey: "b",
+		path:   []string{"a", "[0]"},
+		err:    KeyPathNotFoundError,
+	},
+	{
+		desc:   "missing key",
+		json:   `{"a":1}`,
+		newKey: "b",
+		path:   []string{"c"},
+		err:    KeyPathNotFoundError,
+	},
+}
+
+func TestRename(t *testing.T) {
+	for _, test := range renameTests {
+		data := []byte(test.json)
+		value, err := Rename(data, test.newKey, test.path...)
+
+		if err != test.err {
+			t.Errorf("Rename test '%s' expected error %v, obtained %v", test.desc, test.err, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("Rename test '%s' expected %s, obtained %s", test.desc, test.expected, value)
+		}
+		if string(data) != test.json {
+			t.Errorf("Rename test '%s' modified its input: %s", test.desc, data)
+		}
+	}
+}
+
+var moveTests = []struct {
+	desc     string
+	json     string
+	from, to []string
+	expected string
+	err      error
+}{
+	{
+		desc:     "under another object",
+		json:     `{"a":{"b":1,"x":2},"c":{}}`,
+		from:     []string{"a", "b"},
+		to:       []string{"c", "b"},
+		expected: `{"a":{"x":2},"c":{"b":1}}`,
+	},
+	{
+		desc:     "creating the destination",
+		json:     `{"a":{"b":[1,2]}}`,
+		from:     []string{"a", "b"},
+		to:       []string{"c", "d"},
+		expected: `{"a":{},"c":{"d":[1,2]}}`,
+	},
+	{
+		desc:     "replacing the destination",
+		json:     `{"a":1,"b":2}`,
+		from:     []string{"a"},
+		to:       []string{"b"},
+		expected: `{"b":1}`,
+	},
+	{
+		desc:     "array element to the end of another array",
+		json:     `{"a":[1,2],"b":[3]}`,
+		from:     []string{"a", "[0]"},
+		to:       []string{"b", "[+]"},
+		expected: `{"a":[2],"b":[3,1]}`,
+	},
+	{
+		desc:     "same path",
+		json:     `{"a":1}`,
+		from:     []string{"a"},
+		to:       []string{"a"},
+		expected: `{"a":1}`,
+	},
+	{
+		desc: "into its own child",
+		json: `{"a":{"b":1}}`,
+		from: []string{"a"},
+		to:   []string{"a", "c"},
+		err:  EditConflictError,
+	},
+	{
+		desc: "missing source",
+		json: `{"a":1}`,
+		from: []string{"b"},
+		to:   []string{"c"},
+		err:  KeyPathNotFoundError,
+	},
+}
+
+func TestMove(t *testing.T) {
+	for _, test := range moveTests {
+		data := []byte(test.json)
+		value, err := Move(data, test.from, test.to)
+
+		if err != test.err {
+			t.Errorf("Move test '%s' expected error %v, obtained %v", test.desc, test.err, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("Move test '%s' expected %s, obtained %s", test.desc, test.expected, value)
+		}
+		if string(data) != test.json {
+			t.Errorf("Move test '%s' modified its input: %s", test.desc, data)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package jsonparser
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RawNumber holds the literal text of a JSON number, like json.Number in encoding/json, so callers can decide
+// how to interpret the digits. (The name Number is taken by the ValueType constant.)
+type RawNumber string
+
+// String returns the literal text of the number.
+func (n RawNumber) String() string {
+	return string(n)
+}
+
+// IsInteger reports whether the number is written without a fraction or an exponent.
+func (n RawNumber) IsInteger() bool {
+	return !strings.ContainsAny(string(n), ".eE")
+}
+
+// Int64 returns the number as an int64, failing if it is not an integer or does not fit.
+func (n RawNumber) Int64() (int64, error) {
+	return ParseInt(StringToBytes(string(n)))
+}
+
+// Uint64 returns the number as a uint64, failing if it is not an integer or does not fit.
+func (n RawNumber) Uint64() (uint64, error) {
+	return ParseUint(StringToBytes(string(n)))
+}
+
+// Float64 returns the number as a float64, which may round it.
+func (n RawNumber) Float64() (float64, error) {
+	return ParseFloat(StringToBytes(string(n)))
+}
+
+// BigInt returns the number as a *big.Int. Fractions and exponents are accepted as long as the value is integral, so 1.5e3 gives 1500.
+// Integers of more than a million digits, such as 1e5000000, return OverflowIntegerError.
+func (n RawNumber) BigInt() (*big.Int, error) {
+	if n.IsInteger() {
+		if v, ok := new(big.Int).SetString(string(n), 10); ok {
+			return v, nil
+		}
+		return nil, MalformedValueError
+	}
+
+	b := StringToBytes(string(n))
+	if end, err := validateNumber(b, 0); err != nil || end != len(b) {
+		return nil, MalformedValueError
+	}
+
+	// big.Rat refuses exponents it would take too long to apply, so judge those from the digits instead
+	if exp, zero := bigIntExponent(b); exp > maxBigIntExponent || exp < -maxBigIntExponent {
+		switch {
+		case zero:
+			return new(big.Int), nil
+		case exp > 0:
+			return nil, OverflowIntegerError
+		default:
+			return nil, fmt.Errorf("Value is not an integer: %s", string(n))
+		}
+	}
+
+	// big.Rat parses decimal fractions exactly, unlike big.Float
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return nil, MalformedValueError
+	}
+	if !r.IsInt() {
+		return nil, fmt.Errorf("Value is not an integer: %s", string(n))
+	}
+
+	return new(big.Int).Set(r.Num()), nil
+}
+
+// Integers needing more than this many decimal digits, beyond what big.Rat will parse, do not fit a *big.Int
+const maxBigIntExponent = 1000000
+
+// bigIntExponent returns the power of ten the digits of the valid number b, read as one integer, are scaled by,
+// and whether those digits are all zero. Exponents too large for an int are clamped.
+func bigIntExponent(b []byte) (exp int, zero bool) {
+	if i := bytes.IndexAny(b, "eE"); i != -1 {
+		e := b[i+1:]
+		if e[0] == '+' {
+			e = e[1:]
+		}
+		if v, ok, _ := parseInt(e); ok && v > -maxDecimalExponent && v < maxDecimalExponent {
+			exp = int(v)
+		} else if e[0] == '-' {
+			exp = -maxDecimalExponent
+		} else {
+			exp = maxDecimalExponent
+		}
+		b = b[:i]
+	}
+	if i := bytes.IndexByte(b, '.'); i != -1 {
+		exp -= len(b) - i - 1
+	}
+
+	zero = true
+	for _, c := range b {
+		if c >= '1' && c <= '9' {
+			zero = false
+			break
+		}
+	}
+	return exp, zero
+}
+
+// BigFloat returns the number as a *big.Float, with enough precision to keep every digit of the mantissa.
+// Decimal fractions such as 0.1 have no exact binary representation and are still rounded.
+func (n RawNumber) BigFloat() (*big.Float, error) {
+	// Each decimal digit needs a little under 4 bits
+	prec := uint(len(n))*4 + 64
+
+	v, _, err := big.ParseFloat(string(n), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, MalformedValueError
+	}
+
+	return v, nil
+}
+
+// GetNumber returns the value retrieved by `Get` as a RawNumber, keeping every digit.
+// If key data type do not match, it will return an error.
+func GetNumber(data []byte, keys ...string) (val RawNumber, err error) {
+	v, t, _, e := Get(data, keys...)
+	return numberValue(v, t, e)
+}
+
+func numberValue(v []byte, t ValueType, e error) (RawNumber, error) {
+	if e != nil {
+		return "", e
+	}
+
+	if t != Number {
+		return "", fmt.Errorf("Value is not a number: %s", string(v))
+	}
+
+	return ParseNumber(v)
+}
+
+// ParseNumber parses a Number ValueType into a RawNumber, checking that it is a valid JSON number.
+func ParseNumber(b []byte) (RawNumber, error) {
+	if end, err := validateNumber(b, 0); err != nil || end != len(b) {
+		return "", MalformedValueError
+	}
+
+	return RawNumber(b), nil
+}
+
+// GetBigInt returns the value retrieved by `Get`, cast to a *big.Int if possible, without losing precision.
+// If key data type do not match, or the value is not integral, it will return an error.
+func GetBigInt(data []byte, keys ...string) (val *big.Int, err error) {
+	n, e := GetNumber(data, keys...)
+
+	if e != nil {
+		return nil, e
+	}
+
+	return n.BigInt()
+}
+
+// GetBigFloat returns the value retrieved by `Get`, cast to a *big.Float if possible, without losing precision.
+// If key data type do not match, it will return an error.
+func GetBigFloat(data []byte, keys ...string) (val *big.Float, err error) {
+	n, e := GetNumber(data, keys...)
+
+	if e != nil {
+		return nil, e
+	}
+
+	return n.BigFloat()
+}
+
+// GetDecimal returns the value retrieved by `Get` as an integer count of 10^-scale units, so with a scale of 2
+// 12.34 becomes 1234. No floating point is involved, so values are exact; if the value has more decimal places
+// than scale allows, or does not fit an int64, it will return an error.
+func GetDecimal(data []byte, scale int, keys ...string) (val int64, err error) {
+	v, t, _, e := Get(data, keys...)
+	return decimalValue(v, t, e, scale)
+}
+
+func decimalValue(v []byte, t ValueType, e error, scale int) (int64, error) {
+	if e != nil {
+		return 0, e
+	}
+
+	if t != Number {
+		return 0, fmt.Errorf("Value is not a number: %s", string(v))
+	}
+
+	return ParseDecimal(v, scale)
+}
+
+// Exponents beyond this are clamped; any nonzero value scaled that far cannot fit an int64 anyway
+const maxDecimalExponent = 1 << 30
+
+// ParseDecimal parses a Number ValueType, exponent included, into an integer count of 10^-scale units.
+// It returns DecimalPrecisionError if digits would be dropped and OverflowIntegerError if the result does not fit.
+func ParseDecimal(b []byte, scale int) (int64, error) {
+	if end, err := validateNumber(b, 0); err != nil || end != len(b) {
+		return 0, MalformedValueError
+	}
+
+	neg := b[0] == '-'
+	if neg {
+		b = b[1:]
+	}
+
+	// Split off the exponent
+	exp := 0
+	if i := bytes.IndexAny(b, "eE"); i != -1 {
+		e := b[i+1:]
+		if e[0] == '+' {
+			e = e[1:]
+		}
+		if v, ok, _ := parseInt(e); ok && v > -maxDecimalExponent && v < maxDecimalExponent {
+			exp = int(v)
+		} else if e[0] == '-' {
+			exp = -maxDecimalExponent
+		} else {
+			exp = maxDecimalExponent
+		}
+		b = b[:i]
+	}
+
+	// The value is the integer formed by all the digits, times 10^shift
+	intPart, frac := b, b[:0]
+	if i := bytes.IndexByte(b, '.'); i != -1 {
+		intPart, frac = b[:i], b[i+1:]
+	}
+	shift := exp - len(frac) + scale
+	digitCount := len(intPart) + len(frac)
+	digit := func(i int) uint64 {
+		if i < len(intPart) {
+			return uint64(intPart[i] - '0')
+		}
+		return uint64(frac[i-len(intPart)] - '0')
+	}
+
+	// Digits shifted out must all be zero
+	keep := digitCount
+	if shift < 0 {
+		keep = digitCount + shift
+		if keep < 0 {
+			keep = 0
+		}
+		for i := keep; i < digitCount; i++ {
+			if digit(i) != 0 {
+				return 0, DecimalPrecisionError
+			}
+		}
+		shift = 0
+	}
+
+	var v uint64
+	for i := 0; i < keep; i++ {
+		d := digit(i)
+		if v > (math.MaxUint64-d)/10 {
+			return 0, OverflowIntegerError
+		}
+		v = (10 * v) + d
+	}
+	for ; shift > 0 && v != 0; shift-- {
+		if v > math.MaxUint64/10 {
+			return 0, OverflowIntegerError
+		}
+		v *= 10
+	}
+
+	if neg {
+		// The magnitude of math.MinInt64 is one more than that of math.MaxInt64
+		if v > -math.MinInt64 {
+			return 0, OverflowIntegerError
+		}
+		return -int64(v), nil
+	}
+	if v > math.MaxInt64 {
+		return 0, OverflowIntegerError
+	}
+	return int64(v), nil
+}
+
+// appendFloat appends f to dst in the shortest form that parses back to the same float64, written like
+// encoding/json writes it: plain decimals, and exponents only for very small or very large magnitudes.
+// NaN and infinities have no JSON form and return UnsupportedValueError.
+func appendFloat(dst []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return dst, UnsupportedValueError
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, 64)
+
+	// Turn the two digit negative exponent of e-07 into e-7
+	if n := len(dst); format == 'e' && n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+		dst[n-2] = dst[n-1]
+		dst = dst[:n-1]
+	}
+	return dst, nil
+}
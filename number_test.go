@@ -0,0 +1,210 @@
+package jsonparser
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+var getNumberTests = []GetTest{
+	{
+		desc:    `integer keeps its digits`,
+		json:    `{"a": 123456789012345678901234567890}`,
+		path:    []string{"a"},
+		isFound: true,
+		data:    RawNumber("123456789012345678901234567890"),
+	},
+	{
+		desc:    `decimal keeps its digits`,
+		json:    `{"a": -0.100000000000000000000000000001e+5}`,
+		path:    []string{"a"},
+		isFound: true,
+		data:    RawNumber("-0.100000000000000000000000000001e+5"),
+	},
+	{
+		desc:  `string value`,
+		json:  `{"a": "1"}`,
+		path:  []string{"a"},
+		isErr: true,
+	},
+	{
+		desc:  `malformed number`,
+		json:  `{"a": 1.2.3}`,
+		path:  []string{"a"},
+		isErr: true,
+	},
+	{
+		desc:    `missing key`,
+		json:    `{"a": 1}`,
+		path:    []string{"b"},
+		isFound: false,
+	},
+}
+
+func TestGetNumber(t *testing.T) {
+	runGetTests(t, "GetNumber()", getNumberTests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetNumber([]byte(test.json), test.path...)
+			return value, Number, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(RawNumber)
+			return expected == value.(RawNumber), expected
+		},
+	)
+}
+
+func TestRawNumber(t *testing.T) {
+	tests := []struct {
+		in        RawNumber
+		isInteger bool
+		int64     int64
+		int64Err  bool
+		uint64    uint64
+		uint64Err bool
+		float64   float64
+	}{
+		{in: "42", isInteger: true, int64: 42, uint64: 42, float64: 42},
+		{in: "-42", isInteger: true, int64: -42, uint64Err: true, float64: -42},
+		{in: "18446744073709551615", isInteger: true, int64Err: true, uint64: 18446744073709551615, float64: 18446744073709551615},
+		{in: "1.5", int64Err: true, uint64Err: true, float64: 1.5},
+		{in: "1e3", int64Err: true, uint64Err: true, float64: 1000},
+	}
+
+	for _, test := range tests {
+		if test.in.IsInteger() != test.isInteger {
+			t.Errorf("RawNumber(%s).IsInteger() expected %t", test.in, test.isInteger)
+		}
+		if v, err := test.in.Int64(); (err != nil) != test.int64Err || v != test.int64 {
+			t.Errorf("RawNumber(%s).Int64() returned %d, %v", test.in, v, err)
+		}
+		if v, err := test.in.Uint64(); (err != nil) != test.uint64Err || v != test.uint64 {
+			t.Errorf("RawNumber(%s).Uint64() returned %d, %v", test.in, v, err)
+		}
+		if v, err := test.in.Float64(); err != nil || v != test.float64 {
+			t.Errorf("RawNumber(%s).Float64() returned %g, %v", test.in, v, err)
+		}
+	}
+}
+
+func TestGetBigInt(t *testing.T) {
+	tests := []struct {
+		json  string
+		out   string
+		isErr bool
+	}{
+		{json: `{"a": 123456789012345678901234567890}`, out: "123456789012345678901234567890"},
+		{json: `{"a": -98765432109876543210987654321}`, out: "-98765432109876543210987654321"},
+		{json: `{"a": 1.5e3}`, out: "1500"},
+		{json: `{"a": 12345678901234567890123456789.0}`, out: "12345678901234567890123456789"},
+		{json: `{"a": 1.5}`, isErr: true},
+		{json: `{"a": true}`, isErr: true},
+		{json: `{"a": 1e1000000}`, out: "1" + strings.Repeat("0", 1000000)},
+		{json: `{"a": 0.0e5000000}`, out: "0"},
+		{json: `{"a": -0e-99999999999999999999}`, out: "0"},
+		{json: `{"a": 1.5e-1000000}`, isErr: true},
+	}
+
+	for _, test := range tests {
+		v, err := GetBigInt([]byte(test.json), "a")
+		if (err != nil) != test.isErr {
+			t.Errorf("GetBigInt(%s) isErr mismatch: expected %t, obtained %v", test.json, test.isErr, err)
+		} else if err == nil && v.String() != test.out {
+			t.Errorf("GetBigInt(%s) expected %s, obtained %s", test.json, test.out, v)
+		}
+	}
+
+	// Valid numbers too large for big.Rat overflow rather than being reported as malformed
+	for _, n := range []string{"1e5000000", "-2.5E+1000002", "1e99999999999999999999"} {
+		if _, err := RawNumber(n).BigInt(); err != OverflowIntegerError {
+			t.Errorf("RawNumber(%s).BigInt() expected OverflowIntegerError, obtained %v", n, err)
+		}
+	}
+	if _, err := RawNumber("1e").BigInt(); err != MalformedValueError {
+		t.Errorf("RawNumber(1e).BigInt() expected MalformedValueError, obtained %v", err)
+	}
+}
+
+func TestGetBigFloat(t *testing.T) {
+	data := []byte(`{"amount": 12345678901234567890.123456789, "bad": "1.5"}`)
+
+	v, err := GetBigFloat(data, "amount")
+	if err != nil {
+		t.Fatalf("GetBigFloat returned error %v", err)
+	}
+	if s := v.Text('f', 9); s != "12345678901234567890.123456789" {
+		t.Errorf("GetBigFloat lost precision: %s", s)
+	}
+
+	// float64 cannot hold these digits
+	if f, _ := GetFloat(data, "amount"); big.NewFloat(f).Cmp(v) == 0 {
+		t.Errorf("GetBigFloat should be more precise than GetFloat")
+	}
+
+	if _, err := GetBigFloat(data, "bad"); err == nil {
+		t.Errorf("GetBigFloat on a string should return an error")
+	}
+}
+
+var parseDecimalTests = []struct {
+	in    string
+	scale int
+	out   int64
+	err   error
+}{
+	{in: "0", scale: 2, out: 0},
+	{in: "12.34", scale: 2, out: 1234},
+	{in: "-12.34", scale: 2, out: -1234},
+	{in: "12.3", scale: 2, out: 1230},
+	{in: "12", scale: 2, out: 1200},
+	{in: "12.340000", scale: 2, out: 1234},
+	{in: "0.1", scale: 1, out: 1},
+	{in: "0.30000000000000004", scale: 17, out: 30000000000000004},
+	{in: "1.234e2", scale: 2, out: 12340},
+	{in: "1234E-2", scale: 2, out: 1234},
+	{in: "1.5e+1", scale: 0, out: 15},
+	{in: "1200", scale: -2, out: 12},
+	{in: "0.000e999999999999999999", scale: 2, out: 0},
+	{in: "-9223372036854775808", scale: 0, out: -9223372036854775808},
+	{in: "-92233720368547758.08", scale: 2, out: -9223372036854775808},
+
+	{in: "12.345", scale: 2, err: DecimalPrecisionError},
+	{in: "1e-3", scale: 2, err: DecimalPrecisionError},
+	{in: "1e-999999999999999999", scale: 2, err: DecimalPrecisionError},
+	{in: "1250", scale: -2, err: DecimalPrecisionError},
+	{in: "9223372036854775808", scale: 0, err: OverflowIntegerError},
+	{in: "92233720368547758.08", scale: 2, err: OverflowIntegerError},
+	{in: "1e19", scale: 0, err: OverflowIntegerError},
+	{in: "1e999999999999999999", scale: 0, err: OverflowIntegerError},
+	{in: "1.", scale: 2, err: MalformedValueError},
+	{in: "abc", scale: 2, err: MalformedValueError},
+	{in: "", scale: 2, err: MalformedValueError},
+}
+
+func TestParseDecimal(t *testing.T) {
+	for _, test := range parseDecimalTests {
+		v, err := ParseDecimal([]byte(test.in), test.scale)
+		if err != test.err {
+			t.Errorf("ParseDecimal(%s, %d) expected error %v, obtained %v", test.in, test.scale, test.err, err)
+		} else if v != test.out {
+			t.Errorf("ParseDecimal(%s, %d) expected %d, obtained %d", test.in, test.scale, test.out, v)
+		}
+	}
+}
+
+func TestGetDecimal(t *testing.T) {
+	data := []byte(`{"a": 0.1, "b": 0.2, "c": "0.3"}`)
+
+	a, _ := GetDecimal(data, 2, "a")
+	b, _ := GetDecimal(data, 2, "b")
+	if a+b != 30 {
+		t.Errorf("GetDecimal should be exact, obtained %d + %d", a, b)
+	}
+
+	if _, err := GetDecimal(data, 2, "c"); err == nil {
+		t.Errorf("GetDecimal on a string should return an error")
+	}
+	if _, err := GetDecimal(data, 2, "d"); err != KeyPathNotFoundError {
+		t.Errorf("GetDecimal on a missing key expected KeyPathNotFoundError, obtained %v", err)
+	}
+}
@@ -0,0 +1,758 @@
+package jsonparser
+
+import (
+	"bytes"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Parser bundles the options that change how documents are read and written.
+// The zero value behaves exactly like the package-level functions, which remain the lenient default.
+type Parser struct {
+	// DuplicateKeys selects which member is used when an object repeats a key on the requested path.
+	DuplicateKeys DuplicateKeyPolicy
+
+	// Limits bounds the documents the parser accepts, and the documents its methods produce. Like the other
+	// options, it only applies to the Parser's methods and Editors.
+	Limits Limits
+
+	// Strict rejects documents that are not valid JSON. By default the parser only reads what it needs,
+	// and tolerates malformed input elsewhere in the document.
+	Strict bool
+
+	// CheckUTF8 rejects documents that are not valid UTF-8.
+	CheckUTF8 bool
+
+	// InPlaceUnescape makes ArrayEach and ObjectEach unescape keys and string values into the document itself,
+	// so long strings need no buffer of their own. The document must be writable, and is no longer valid
+	// JSON once the callbacks have run.
+	InPlaceUnescape bool
+
+	// FoldKeys matches keys case-insensitively under Unicode simple folding, like encoding/json matches
+	// field names: a member whose key matches exactly is preferred, and members that only match when folded
+	// are used otherwise. DuplicateKeys selects between members with the same key; members that only differ
+	// in case are not duplicates, and RejectDuplicateKeys uses the first of them.
+	FoldKeys bool
+
+	// PreserveIndentation makes Set put new members of existing objects and arrays on their own lines,
+	// indented like the members around them, and indent any objects and arrays it creates to hold the value.
+	// Documents on a single line stay on a single line.
+	PreserveIndentation bool
+}
+
+// Option configures a Parser created by New.
+type Option func(*Parser)
+
+// New returns a Parser configured with the given options.
+func New(opts ...Option) *Parser {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithDuplicateKeys sets the policy used when an object repeats a key.
+func WithDuplicateKeys(policy DuplicateKeyPolicy) Option {
+	return func(p *Parser) {
+		p.DuplicateKeys = policy
+	}
+}
+
+// WithLimits bounds the documents the parser accepts.
+func WithLimits(limits Limits) Option {
+	return func(p *Parser) {
+		p.Limits = limits
+	}
+}
+
+// WithStrict makes the parser validate whole documents, rejecting any that are not valid JSON.
+func WithStrict() Option {
+	return func(p *Parser) {
+		p.Strict = true
+	}
+}
+
+// WithUTF8Check makes the parser reject documents that are not valid UTF-8.
+func WithUTF8Check() Option {
+	return func(p *Parser) {
+		p.CheckUTF8 = true
+	}
+}
+
+// WithInPlaceUnescape makes ArrayEach and ObjectEach unescape strings into the document they iterate over.
+func WithInPlaceUnescape() Option {
+	return func(p *Parser) {
+		p.InPlaceUnescape = true
+	}
+}
+
+// WithFoldedKeys makes the parser match keys case-insensitively, so "userId" also finds "UserID".
+func WithFoldedKeys() Option {
+	return func(p *Parser) {
+		p.FoldKeys = true
+	}
+}
+
+// WithPreservedIndentation makes Set lay out new members like the existing ones, so pretty-printed
+// documents stay pretty-printed.
+func WithPreservedIndentation() Option {
+	return func(p *Parser) {
+		p.PreserveIndentation = true
+	}
+}
+
+// defaultParser backs the package-level functions that share their implementation with Parser.
+var defaultParser Parser
+
+// usesDefaults reports whether the parser can take the package-level fast paths.
+// Limits, strictness and UTF-8 are checked up front and so do not affect the paths taken.
+func (p *Parser) usesDefaults() bool {
+	return p.DuplicateKeys == FirstKeyWins && !p.FoldKeys
+}
+
+// check returns an error if data breaks the parser's limits, strictness or encoding requirements.
+func (p *Parser) check(data []byte) error {
+	if err := p.Limits.check(data); err != nil {
+		return err
+	}
+	if p.CheckUTF8 && !utf8.Valid(data) {
+		return InvalidUTF8Error
+	}
+	if p.Strict {
+		return validate(data)
+	}
+	return nil
+}
+
+// Get behaves like the package-level Get, honoring the parser's options.
+func (p *Parser) Get(data []byte, keys ...string) (value []byte, dataType ValueType, offset int, err error) {
+	if err := p.check(data); err != nil {
+		return nil, NotExist, -1, err
+	}
+
+	a, b, _, d, e := p.internalGet(data, keys...)
+	return a, b, d, e
+}
+
+// EachKey behaves like the package-level EachKey, honoring the parser's options.
+// When non-default options are set every path is resolved separately, so callbacks are invoked in path order.
+func (p *Parser) EachKey(data []byte, cb func(int, []byte, ValueType, error), paths ...[]string) int {
+	if err := p.check(data); err != nil {
+		cb(-1, nil, Unknown, err)
+		return -1
+	}
+
+	if p.usesDefaults() {
+		return EachKey(data, cb, paths...)
+	}
+
+	offset := 0
+	for pi, path := range paths {
+		v, dt, _, end, err := p.internalGet(data, path...)
+		if err == KeyPathNotFoundError {
+			offset = -1
+			continue
+		}
+
+		cb(pi, v, dt, err)
+
+		if offset != -1 && end > offset {
+			offset = end
+		}
+	}
+
+	return offset
+}
+
+// Set behaves like the package-level Set, honoring the parser's options.
+func (p *Parser) Set(data []byte, setValue []byte, keys ...string) (value []byte, err error) {
+	return p.AppendSet(nil, data, setValue, keys...)
+}
+
+// AppendSet behaves like the package-level AppendSet, honoring the parser's options.
+func (p *Parser) AppendSet(dst, data []byte, setValue []byte, keys ...string) (value []byte, err error) {
+	if err := p.check(data); err != nil {
+		return nil, err
+	}
+
+	return p.checked(p.appendSet(dst, data, setValue, keys...))
+}
+
+// SetString behaves like the package-level SetString, honoring the parser's options.
+func (p *Parser) SetString(data []byte, val string, keys ...string) (value []byte, err error) {
+	return p.Set(data, AppendQuoted(nil, val), keys...)
+}
+
+// SetInt behaves like the package-level SetInt, honoring the parser's options.
+func (p *Parser) SetInt(data []byte, val int64, keys ...string) (value []byte, err error) {
+	return p.Set(data, strconv.AppendInt(nil, val, 10), keys...)
+}
+
+// SetFloat behaves like the package-level SetFloat, honoring the parser's options.
+func (p *Parser) SetFloat(data []byte, val float64, keys ...string) (value []byte, err error) {
+	v, err := appendFloat(nil, val)
+	if err != nil {
+		return nil, err
+	}
+	return p.Set(data, v, keys...)
+}
+
+// SetBool behaves like the package-level SetBool, honoring the parser's options.
+func (p *Parser) SetBool(data []byte, val bool, keys ...string) (value []byte, err error) {
+	if val {
+		return p.Set(data, trueLiteral, keys...)
+	}
+	return p.Set(data, falseLiteral, keys...)
+}
+
+// SetNull behaves like the package-level SetNull, honoring the parser's options.
+func (p *Parser) SetNull(data []byte, keys ...string) (value []byte, err error) {
+	return p.Set(data, nullLiteral, keys...)
+}
+
+// Delete behaves like the package-level Delete, honoring the parser's options.
+// Documents failing the parser's checks are returned unchanged.
+func (p *Parser) Delete(data []byte, keys ...string) []byte {
+	if p.check(data) != nil {
+		return data
+	}
+
+	value, _, _ := p.deleteE(data, keys...)
+	return value
+}
+
+// DeleteInPlace behaves like the package-level DeleteInPlace, honoring the parser's options.
+// Documents failing the parser's checks are returned unchanged.
+func (p *Parser) DeleteInPlace(data []byte, keys ...string) []byte {
+	if p.check(data) != nil {
+		return data
+	}
+
+	return p.deleteInPlace(data, keys...)
+}
+
+// AppendDelete behaves like the package-level AppendDelete, honoring the parser's options.
+// Documents failing the parser's checks are copied into dst unchanged.
+func (p *Parser) AppendDelete(dst, data []byte, keys ...string) []byte {
+	if p.check(data) != nil {
+		return append(dst[:0], data...)
+	}
+
+	return p.appendDelete(dst, data, keys...)
+}
+
+// DeleteE behaves like the package-level DeleteE, honoring the parser's options.
+// Documents failing the parser's checks are returned unchanged, along with the error.
+func (p *Parser) DeleteE(data []byte, keys ...string) (value []byte, found bool, err error) {
+	if err := p.check(data); err != nil {
+		return data, false, err
+	}
+
+	return p.deleteE(data, keys...)
+}
+
+// ArrayEach behaves like the package-level ArrayEach, honoring the parser's options.
+func (p *Parser) ArrayEach(data []byte, cb func(value []byte, dataType ValueType, offset int, err error), keys ...string) (offset int, err error) {
+	if err := p.check(data); err != nil {
+		return -1, err
+	}
+
+	if p.usesDefaults() || len(keys) == 0 {
+		return arrayEach(data, cb, p.InPlaceUnescape, keys...)
+	}
+
+	_, start, _, err := p.lookup(data, keys)
+	if err != nil {
+		return start, err
+	}
+	if data[start] != '[' {
+		return start, MalformedArrayError
+	}
+
+	// Report offsets relative to data rather than to the array
+	offset, err = arrayEach(data[start:], func(value []byte, dataType ValueType, offset int, err error) {
+		cb(value, dataType, start+offset, err)
+	}, p.InPlaceUnescape)
+	return start + offset, err
+}
+
+// ObjectEach behaves like the package-level ObjectEach, honoring the parser's options.
+func (p *Parser) ObjectEach(data []byte, callback func(key []byte, value []byte, dataType ValueType, offset int) error, keys ...string) (err error) {
+	if err := p.check(data); err != nil {
+		return err
+	}
+
+	if p.usesDefaults() || len(keys) == 0 {
+		return objectEach(data, callback, p.InPlaceUnescape, keys...)
+	}
+
+	_, start, _, err := p.lookup(data, keys)
+	if err != nil {
+		return err
+	}
+
+	// Report offsets relative to data rather than to the object
+	return objectEach(data[start:], func(key []byte, value []byte, dataType ValueType, offset int) error {
+		return callback(key, value, dataType, start+offset)
+	}, p.InPlaceUnescape)
+}
+
+// GetUnsafeString behaves like the package-level GetUnsafeString, honoring the parser's options.
+func (p *Parser) GetUnsafeString(data []byte, keys ...string) (val string, err error) {
+	v, _, _, e := p.Get(data, keys...)
+	return unsafeStringValue(v, e)
+}
+
+// GetString behaves like the package-level GetString, honoring the parser's options.
+func (p *Parser) GetString(data []byte, keys ...string) (val string, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return stringValue(v, t, e)
+}
+
+// GetStringView behaves like the package-level GetStringView, honoring the parser's options.
+func (p *Parser) GetStringView(data []byte, buf []byte, keys ...string) (val string, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return stringViewValue(v, t, e, buf)
+}
+
+// GetFloat behaves like the package-level GetFloat, honoring the parser's options.
+func (p *Parser) GetFloat(data []byte, keys ...string) (val float64, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return floatValue(v, t, e)
+}
+
+// GetInt behaves like the package-level GetInt, honoring the parser's options.
+func (p *Parser) GetInt(data []byte, keys ...string) (val int64, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return intValue(v, t, e)
+}
+
+// GetInt32 behaves like the package-level GetInt32, honoring the parser's options.
+func (p *Parser) GetInt32(data []byte, keys ...string) (val int32, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return int32Value(v, t, e)
+}
+
+// GetUint64 behaves like the package-level GetUint64, honoring the parser's options.
+func (p *Parser) GetUint64(data []byte, keys ...string) (val uint64, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return uint64Value(v, t, e)
+}
+
+// GetUint32 behaves like the package-level GetUint32, honoring the parser's options.
+func (p *Parser) GetUint32(data []byte, keys ...string) (val uint32, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return uint32Value(v, t, e)
+}
+
+// GetBoolean behaves like the package-level GetBoolean, honoring the parser's options.
+func (p *Parser) GetBoolean(data []byte, keys ...string) (val bool, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return booleanValue(v, t, e)
+}
+
+// GetNumber behaves like the package-level GetNumber, honoring the parser's options.
+func (p *Parser) GetNumber(data []byte, keys ...string) (val RawNumber, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return numberValue(v, t, e)
+}
+
+// GetBigInt behaves like the package-level GetBigInt, honoring the parser's options.
+func (p *Parser) GetBigInt(data []byte, keys ...string) (val *big.Int, err error) {
+	n, e := p.GetNumber(data, keys...)
+	if e != nil {
+		return nil, e
+	}
+	return n.BigInt()
+}
+
+// GetBigFloat behaves like the package-level GetBigFloat, honoring the parser's options.
+func (p *Parser) GetBigFloat(data []byte, keys ...string) (val *big.Float, err error) {
+	n, e := p.GetNumber(data, keys...)
+	if e != nil {
+		return nil, e
+	}
+	return n.BigFloat()
+}
+
+// GetDecimal behaves like the package-level GetDecimal, honoring the parser's options.
+func (p *Parser) GetDecimal(data []byte, scale int, keys ...string) (val int64, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return decimalValue(v, t, e, scale)
+}
+
+// GetIntCoerce behaves like the package-level GetIntCoerce, honoring the parser's options.
+func (p *Parser) GetIntCoerce(data []byte, keys ...string) (val int64, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return intCoerceValue(unwrapValue(v, t, e))
+}
+
+// GetFloatCoerce behaves like the package-level GetFloatCoerce, honoring the parser's options.
+func (p *Parser) GetFloatCoerce(data []byte, keys ...string) (val float64, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return floatCoerceValue(unwrapValue(v, t, e))
+}
+
+// GetBoolCoerce behaves like the package-level GetBoolCoerce, honoring the parser's options.
+func (p *Parser) GetBoolCoerce(data []byte, keys ...string) (val bool, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return boolCoerceValue(unwrapValue(v, t, e))
+}
+
+// GetStringCoerce behaves like the package-level GetStringCoerce, honoring the parser's options.
+func (p *Parser) GetStringCoerce(data []byte, keys ...string) (val string, err error) {
+	v, t, _, e := p.Get(data, keys...)
+	return stringCoerceValue(unwrapValue(v, t, e))
+}
+
+// Insert behaves like the package-level Insert, honoring the parser's options.
+func (p *Parser) Insert(data []byte, value []byte, keys ...string) ([]byte, error) {
+	if err := p.check(data); err != nil {
+		return nil, err
+	}
+
+	return p.checked(p.insert(data, value, keys...))
+}
+
+// ArrayRemove behaves like the package-level ArrayRemove, honoring the parser's options.
+func (p *Parser) ArrayRemove(data []byte, from, to int, keys ...string) ([]byte, error) {
+	if err := p.check(data); err != nil {
+		return nil, err
+	}
+
+	return p.arrayRemove(data, from, to, keys...)
+}
+
+// ArrayAppend behaves like the package-level ArrayAppend, honoring the parser's options.
+func (p *Parser) ArrayAppend(data []byte, keys []string, values ...[]byte) ([]byte, error) {
+	if err := p.check(data); err != nil {
+		return nil, err
+	}
+
+	return p.checked(p.arrayAppend(data, keys, values...))
+}
+
+// Rename behaves like the package-level Rename, honoring the parser's options.
+func (p *Parser) Rename(data []byte, newKey string, keys ...string) ([]byte, error) {
+	if err := p.check(data); err != nil {
+		return nil, err
+	}
+
+	return p.checked(p.rename(data, newKey, keys...))
+}
+
+// The methods below check the documents they read and write like the others, but walk them like the
+// package-level functions do: DuplicateKeys and FoldKeys do not change which members they use.
+
+// Edit returns an Editor for data whose Apply checks data and the result against the parser's options.
+func (p *Parser) Edit(data []byte) *Editor {
+	return &Editor{data: data, p: p}
+}
+
+// DeleteMany behaves like the package-level DeleteMany, checking data against the parser's options.
+func (p *Parser) DeleteMany(data []byte, paths ...[]string) ([]byte, error) {
+	return p.deleteMany(data, paths...)
+}
+
+// Move behaves like the package-level Move, checking data and the result against the parser's options.
+func (p *Parser) Move(data []byte, from, to []string) ([]byte, error) {
+	if err := p.check(data); err != nil {
+		return nil, err
+	}
+
+	return p.move(data, from, to)
+}
+
+// ApplyPatch behaves like the package-level ApplyPatch, checking doc, patch and the result against the
+// parser's options.
+func (p *Parser) ApplyPatch(doc, patch []byte) ([]byte, error) {
+	if err := p.check(doc); err != nil {
+		return nil, err
+	}
+	if err := p.check(patch); err != nil {
+		return nil, err
+	}
+
+	return p.checked(ApplyPatch(doc, patch))
+}
+
+// MergePatch behaves like the package-level MergePatch, checking target, patch and the result against the
+// parser's options.
+func (p *Parser) MergePatch(target, patch []byte) ([]byte, error) {
+	if err := p.check(target); err != nil {
+		return nil, err
+	}
+	if err := p.check(patch); err != nil {
+		return nil, err
+	}
+
+	return p.checked(MergePatch(target, patch))
+}
+
+// Diff behaves like the package-level Diff, checking a and b against the parser's options.
+func (p *Parser) Diff(a, b []byte, opts ...DiffOption) ([]byte, error) {
+	if err := p.check(a); err != nil {
+		return nil, err
+	}
+	if err := p.check(b); err != nil {
+		return nil, err
+	}
+
+	return Diff(a, b, opts...)
+}
+
+// Indent behaves like the package-level Indent, checking src against the parser's options.
+func (p *Parser) Indent(dst, src []byte, prefix, indent string, opts ...IndentOption) ([]byte, error) {
+	if err := p.check(src); err != nil {
+		return nil, err
+	}
+
+	return Indent(dst, src, prefix, indent, opts...)
+}
+
+// Compact behaves like the package-level Compact, checking src against the parser's options.
+func (p *Parser) Compact(dst, src []byte) ([]byte, error) {
+	if err := p.check(src); err != nil {
+		return nil, err
+	}
+
+	return Compact(dst, src)
+}
+
+// FindDuplicateKeys behaves like the package-level FindDuplicateKeys, checking data against the parser's options.
+func (p *Parser) FindDuplicateKeys(data []byte) ([][]string, error) {
+	if err := p.check(data); err != nil {
+		return nil, err
+	}
+
+	return FindDuplicateKeys(data)
+}
+
+// checked returns value, the document written by a method, if it passes the parser's checks as well.
+// This covers the values written into it, and any objects or arrays created to hold them.
+func (p *Parser) checked(value []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := p.check(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (p *Parser) internalGet(data []byte, keys ...string) (value []byte, dataType ValueType, offset, endOffset int, err error) {
+	if p.usesDefaults() {
+		return internalGet(data, keys...)
+	}
+
+	if _, offset, _, err = p.lookup(data, keys); err != nil {
+		if err == KeyPathNotFoundError {
+			return nil, NotExist, -1, -1, err
+		}
+		return nil, NotExist, offset, -1, err
+	}
+
+	value, dataType, endOffset, err = getType(data, offset)
+	if err != nil {
+		return value, dataType, offset, endOffset, err
+	}
+
+	// Strip quotes from string values
+	if dataType == String {
+		value = value[1 : len(value)-1]
+	}
+
+	return value, dataType, offset, endOffset, nil
+}
+
+// lookup resolves keys one segment at a time, which lets it apply options that the single pass
+// of searchKeys cannot. It returns the offset of the member's key when the value is an object
+// member (-1 otherwise), and the offsets where the value starts and ends.
+func (p *Parser) lookup(data []byte, keys []string) (keyOffset, offset, endOffset int, err error) {
+	keyOffset = -1
+	if offset = nextToken(data); offset == -1 {
+		return -1, -1, -1, MalformedJsonError
+	}
+
+	for _, key := range keys {
+		switch data[offset] {
+		case '{':
+			keyOffset, offset, err = p.findMember(data, offset, segmentName(key))
+		case '[':
+			keyOffset = -1
+			if len(key) < 2 || key[0] != '[' || key[len(key)-1] != ']' {
+				return -1, -1, -1, KeyPathNotFoundError
+			}
+			// Append and prepend keys never name an existing element, and would make Atoi allocate its error
+			idxVal := key[1 : len(key)-1]
+			if idxVal == "+" || idxVal == "-" {
+				return -1, -1, -1, KeyPathNotFoundError
+			}
+			idx, aErr := strconv.Atoi(idxVal)
+			if aErr != nil || idx < 0 {
+				return -1, -1, -1, KeyPathNotFoundError
+			}
+			offset, err = findElement(data, offset, idx)
+		default:
+			return -1, -1, -1, KeyPathNotFoundError
+		}
+
+		if err != nil {
+			return -1, offset, -1, err
+		}
+	}
+
+	if _, _, endOffset, err = getType(data, offset); err != nil {
+		return -1, offset, -1, err
+	}
+
+	return keyOffset, offset, endOffset, nil
+}
+
+// findMember locates key within the object starting at data[start] according to the duplicate key policy.
+// It returns the offsets of the member's key and of its value. With FoldKeys, a member whose key equals
+// key exactly is preferred over members that only match when folded, as in encoding/json.
+func (p *Parser) findMember(data []byte, start int, key string) (keyOffset, valueOffset int, err error) {
+	var stackbuf [unescapeStackBufSize]byte // stack-allocated array for allocation-free unescaping of small strings
+	var foldbuf [unescapeStackBufSize]byte  // holds the key of the first folded match, to spot repeats of it
+	keyOffset, valueOffset = -1, -1
+	foldKeyOffset, foldValueOffset := -1, -1
+	var foldKey []byte
+	foldRepeated := false
+	offset := start + 1
+
+	// Stop early on an empty object
+	if off := nextToken(data[offset:]); off != -1 && data[offset+off] == '}' {
+		return -1, start, KeyPathNotFoundError
+	}
+
+	for {
+		// Find the next key
+		off := nextToken(data[offset:])
+		if off == -1 {
+			return -1, start, MalformedObjectError
+		}
+		offset += off
+		if data[offset] != '"' {
+			return -1, start, MalformedObjectError
+		}
+
+		kOffset := offset
+		strEnd, keyEscaped := stringEnd(data[offset+1:])
+		if strEnd == -1 {
+			return -1, start, MalformedStringError
+		}
+		k := data[offset+1 : offset+strEnd]
+		offset += strEnd + 1
+
+		if keyEscaped {
+			if ku, err := Unescape(k, stackbuf[:]); err != nil {
+				return -1, start, MalformedStringEscapeError
+			} else {
+				k = ku
+			}
+		}
+
+		// Skip the colon and find the value
+		if off = nextToken(data[offset:]); off == -1 || data[offset+off] != ':' {
+			return -1, start, MalformedJsonError
+		}
+		offset += off + 1
+		if off = nextToken(data[offset:]); off == -1 {
+			return -1, start, MalformedJsonError
+		}
+		offset += off
+
+		if equalStr(&k, key) {
+			if keyOffset != -1 && p.DuplicateKeys == RejectDuplicateKeys {
+				return -1, start, DuplicateKeyError
+			}
+			if keyOffset == -1 || p.DuplicateKeys == LastKeyWins {
+				keyOffset, valueOffset = kOffset, offset
+			}
+			if p.DuplicateKeys == FirstKeyWins {
+				return keyOffset, valueOffset, nil
+			}
+		} else if p.FoldKeys && bytes.EqualFold(k, StringToBytes(key)) {
+			// Members that only differ in case are not duplicates of each other
+			switch {
+			case foldKeyOffset == -1:
+				foldKeyOffset, foldValueOffset = kOffset, offset
+				foldKey = append(foldbuf[:0], k...)
+			case p.DuplicateKeys == LastKeyWins:
+				foldKeyOffset, foldValueOffset = kOffset, offset
+			case p.DuplicateKeys == RejectDuplicateKeys && bytes.Equal(k, foldKey):
+				foldRepeated = true
+			}
+		}
+
+		_, _, end, err := getType(data, offset)
+		if err != nil {
+			return -1, start, err
+		}
+		offset = end
+
+		// Skip over the comma, or stop at the closing brace
+		if off = nextToken(data[offset:]); off == -1 {
+			return -1, start, MalformedObjectError
+		}
+		offset += off
+		if data[offset] == '}' {
+			break
+		} else if data[offset] != ',' {
+			return -1, start, MalformedObjectError
+		}
+		offset++
+	}
+
+	if keyOffset != -1 {
+		return keyOffset, valueOffset, nil
+	}
+	if foldKeyOffset != -1 {
+		if foldRepeated {
+			return -1, start, DuplicateKeyError
+		}
+		return foldKeyOffset, foldValueOffset, nil
+	}
+
+	return -1, start, KeyPathNotFoundError
+}
+
+// findElement returns the offset of the idx'th element of the array starting at data[start].
+func findElement(data []byte, start, idx int) (int, error) {
+	offset := start + 1
+
+	for i := 0; ; i++ {
+		off := nextToken(data[offset:])
+		if off == -1 {
+			return start, MalformedArrayError
+		}
+		offset += off
+		if data[offset] == ']' && i == 0 {
+			return start, KeyPathNotFoundError
+		}
+		if i == idx {
+			return offset, nil
+		}
+
+		_, _, end, err := getType(data, offset)
+		if err != nil {
+			return start, err
+		}
+		offset = end
+
+		// Skip over the comma, or stop at the closing bracket
+		if off = nextToken(data[offset:]); off == -1 {
+			return start, MalformedArrayError
+		}
+		offset += off
+		if data[offset] == ']' {
+			return start, KeyPathNotFoundError
+		} else if data[offset] != ',' {
+			return start, MalformedArrayError
+		}
+		offset++
+	}
+}
@@ -0,0 +1,343 @@
+package jsonparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	limits := Limits{MaxDepth: 10}
+	p := New(WithDuplicateKeys(LastKeyWins), WithLimits(limits), WithStrict(), WithUTF8Check(), WithInPlaceUnescape(), WithFoldedKeys(),
+		WithPreservedIndentation())
+
+	if p.DuplicateKeys != LastKeyWins || p.Limits != limits || !p.Strict || !p.CheckUTF8 || !p.InPlaceUnescape || !p.FoldKeys ||
+		!p.PreserveIndentation {
+		t.Errorf("New did not apply all options: %+v", *p)
+	}
+
+	if *New() != (Parser{}) {
+		t.Errorf("New without options should return the default parser")
+	}
+}
+
+func TestStrictParser(t *testing.T) {
+	p := New(WithStrict())
+
+	// The lenient parser reads the key despite the missing closing brace
+	if _, _, _, err := Get([]byte(`{"a":1 `), "a"); err != nil {
+		t.Errorf("Package-level Get should stay lenient, obtained %v", err)
+	}
+	if _, _, _, err := p.Get([]byte(`{"a":1 `), "a"); err != MalformedObjectError {
+		t.Errorf("Strict Get expected MalformedObjectError, obtained %v", err)
+	}
+	if v, _, _, err := p.Get([]byte(`{"a":1}`), "a"); err != nil || string(v) != "1" {
+		t.Errorf("Strict Get on valid document returned %s, %v", v, err)
+	}
+
+	if _, err := p.Set([]byte(`{"a":1}`), []byte(`{"b":}`), "a"); err == nil {
+		t.Errorf("Strict Set should reject a malformed value")
+	}
+	if v, err := p.Set([]byte(`{"a":1}`), []byte(`{"b":2}`), "a"); err != nil || string(v) != `{"a":{"b":2}}` {
+		t.Errorf("Strict Set on valid input returned %s, %v", v, err)
+	}
+
+	malformed := []byte(`{"a":"b":"c"}`)
+	if v := p.Delete(malformed, "a"); string(v) != string(malformed) {
+		t.Errorf("Strict Delete should leave malformed documents unchanged, obtained %s", v)
+	}
+
+	if _, err := p.ArrayEach([]byte(`[1,2,]`), func([]byte, ValueType, int, error) {}); err == nil {
+		t.Errorf("Strict ArrayEach should reject a trailing comma")
+	}
+	if err := p.ObjectEach([]byte(`{"a":01}`), func([]byte, []byte, ValueType, int) error { return nil }); err == nil {
+		t.Errorf("Strict ObjectEach should reject a leading zero")
+	}
+}
+
+func TestUTF8CheckParser(t *testing.T) {
+	p := New(WithUTF8Check())
+	invalid := []byte("{\"a\":\"\xff\"}")
+
+	if _, _, _, err := Get(invalid, "a"); err != nil {
+		t.Errorf("Package-level Get should not check UTF-8, obtained %v", err)
+	}
+	if _, _, _, err := p.Get(invalid, "a"); err != InvalidUTF8Error {
+		t.Errorf("Get expected InvalidUTF8Error, obtained %v", err)
+	}
+	if v, _, _, err := p.Get([]byte(`{"a":"é"}`), "a"); err != nil || string(v) != "é" {
+		t.Errorf("Get on valid UTF-8 returned %s, %v", v, err)
+	}
+	if _, err := p.Set([]byte(`{}`), []byte("\"\xc3\""), "a"); err != InvalidUTF8Error {
+		t.Errorf("Set expected InvalidUTF8Error, obtained %v", err)
+	}
+}
+
+func TestParserArrayEachAndObjectEachOffsets(t *testing.T) {
+	data := []byte(`{"a":{"x":[10,20]},"a":{"x":[30,40],"y":{"k":"v"}}}`)
+	p := &Parser{DuplicateKeys: LastKeyWins}
+
+	var values []string
+	p.ArrayEach(data, func(value []byte, dataType ValueType, offset int, err error) {
+		values = append(values, string(value))
+		if string(data[offset:offset+len(value)]) != string(value) {
+			t.Errorf("ArrayEach offset %d does not point at %s", offset, value)
+		}
+	}, "a", "x")
+	if strings.Join(values, ",") != "30,40" {
+		t.Errorf("ArrayEach visited %v", values)
+	}
+
+	var keys []string
+	err := p.ObjectEach(data, func(key []byte, value []byte, dataType ValueType, offset int) error {
+		keys = append(keys, string(key))
+		if data[offset-1] != '"' {
+			t.Errorf("ObjectEach offset %d does not point past %s", offset, value)
+		}
+		return nil
+	}, "a", "y")
+	if err != nil || strings.Join(keys, ",") != "k" {
+		t.Errorf("ObjectEach visited %v (err %v)", keys, err)
+	}
+}
+
+func TestInPlaceUnescapeParser(t *testing.T) {
+	p := New(WithInPlaceUnescape())
+	long := strings.Repeat("x", 2*unescapeStackBufSize)
+
+	data := []byte(`{"a\u0062":"c\nd","` + long + `\"":"` + long + `\u00e9","n":[1,"\u0041"]}`)
+	var got []string
+	err := p.ObjectEach(data, func(key []byte, value []byte, dataType ValueType, offset int) error {
+		got = append(got, string(key)+"="+string(value))
+		return nil
+	})
+	expected := []string{"ab=c\nd", long + "\"=" + long + "é", `n=[1,"\u0041"]`}
+	if err != nil || !reflect.DeepEqual(got, expected) {
+		t.Errorf("ObjectEach returned %q, %v; expected %q", got, err, expected)
+	}
+
+	data = []byte(`{"a":[1,"\u0041",{"b":"\t"}]}`)
+	got = got[:0]
+	_, err = p.ArrayEach(data, func(value []byte, dataType ValueType, offset int, err error) {
+		got = append(got, string(value))
+	}, "a")
+	expected = []string{"1", "A", `{"b":"\t"}`}
+	if err != nil || !reflect.DeepEqual(got, expected) {
+		t.Errorf("ArrayEach returned %q, %v; expected %q", got, err, expected)
+	}
+
+	if _, err := p.ArrayEach([]byte(`["\x"]`), func([]byte, ValueType, int, error) {}); err != MalformedStringEscapeError {
+		t.Errorf("ArrayEach expected MalformedStringEscapeError, obtained %v", err)
+	}
+
+	// Long keys and values are unescaped without allocating
+	orig := []byte(`{"` + long + `\n":"` + long + `\n"}`)
+	buf := make([]byte, len(orig))
+	cb := func(key []byte, value []byte, dataType ValueType, offset int) error { return nil }
+	allocs := testing.AllocsPerRun(100, func() {
+		copy(buf, orig)
+		if err := p.ObjectEach(buf, cb); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ObjectEach with in-place unescaping allocated %v times", allocs)
+	}
+}
+
+func TestFoldedKeysParser(t *testing.T) {
+	p := New(WithFoldedKeys())
+	data := []byte(`{"User":{"userId":1,"Ünïcode":"a","\u004bey":"b"},"list":[{"ID":2}]}`)
+
+	getTests := []struct {
+		path     []string
+		expected string
+	}{
+		{[]string{"user", "UserID"}, "1"},
+		{[]string{"USER", "userid"}, "1"},
+		{[]string{"user", "üNÏCODE"}, "a"},
+		{[]string{"user", "kEY"}, "b"},
+		{[]string{"LIST", "[0]", "id"}, "2"},
+	}
+	for _, test := range getTests {
+		if v, _, _, err := p.Get(data, test.path...); err != nil || string(v) != test.expected {
+			t.Errorf("Get(%q) returned %s, %v; expected %s", test.path, v, err, test.expected)
+		}
+	}
+
+	if _, _, _, err := Get(data, "user", "userid"); err != KeyPathNotFoundError {
+		t.Errorf("Package-level Get should match keys exactly, obtained %v", err)
+	}
+	if _, _, _, err := p.Get(data, "user", "userids"); err != KeyPathNotFoundError {
+		t.Errorf("Folding should not match keys of different lengths, obtained %v", err)
+	}
+
+	var found []string
+	p.EachKey(data, func(idx int, value []byte, vt ValueType, err error) {
+		found = append(found, string(value))
+	}, []string{"user", "USERID"}, []string{"list", "[0]", "Id"})
+	if !reflect.DeepEqual(found, []string{"1", "2"}) {
+		t.Errorf("EachKey returned %q", found)
+	}
+
+	if v, err := p.Set(data, []byte(`3`), "user", "USERID"); err != nil || string(v) != `{"User":{"userId":3,"Ünïcode":"a","\u004bey":"b"},"list":[{"ID":2}]}` {
+		t.Errorf("Set returned %s, %v", v, err)
+	}
+	if v := p.Delete(data, "user", "KEY"); string(v) != `{"User":{"userId":1,"Ünïcode":"a"},"list":[{"ID":2}]}` {
+		t.Errorf("Delete returned %s", v)
+	}
+	if v, found, err := p.DeleteE(data, "user", "KEY"); !found || err != nil || string(v) != `{"User":{"userId":1,"Ünïcode":"a"},"list":[{"ID":2}]}` {
+		t.Errorf("DeleteE returned %s, %t, %v", v, found, err)
+	}
+	if v, err := p.Insert(data, []byte(`{}`), "LIST", "[0]"); err != nil || string(v) != `{"User":{"userId":1,"Ünïcode":"a","\u004bey":"b"},"list":[{},{"ID":2}]}` {
+		t.Errorf("Insert returned %s, %v", v, err)
+	}
+	if v, err := p.Rename(data, "id", "user", "USERID"); err != nil || string(v) != `{"User":{"id":1,"Ünïcode":"a","\u004bey":"b"},"list":[{"ID":2}]}` {
+		t.Errorf("Rename returned %s, %v", v, err)
+	}
+	if v, err := p.GetBigInt(data, "list", "[0]", "id"); err != nil || v.Int64() != 2 {
+		t.Errorf("GetBigInt returned %v, %v", v, err)
+	}
+	if v, err := p.GetStringCoerce(data, "user", "userid"); err != nil || v != "1" {
+		t.Errorf("GetStringCoerce returned %s, %v", v, err)
+	}
+
+	// Policies choose between members that only differ in case
+	dup := []byte(`{"id":1,"ID":2}`)
+	if v, _, _, _ := p.Get(dup, "Id"); string(v) != "1" {
+		t.Errorf("FirstKeyWins expected 1, obtained %s", v)
+	}
+	if v, _, _, _ := New(WithFoldedKeys(), WithDuplicateKeys(LastKeyWins)).Get(dup, "Id"); string(v) != "2" {
+		t.Errorf("LastKeyWins expected 2, obtained %s", v)
+	}
+	if v, _, _, err := New(WithFoldedKeys(), WithDuplicateKeys(RejectDuplicateKeys)).Get(dup, "Id"); err != nil || string(v) != "1" {
+		t.Errorf("RejectDuplicateKeys expected 1, obtained %s, %v", v, err)
+	}
+}
+
+func TestFoldedKeysPreferExactMatch(t *testing.T) {
+	data := []byte(`{"ID":1,"id":2,"Id":3}`)
+	for _, policy := range []DuplicateKeyPolicy{FirstKeyWins, LastKeyWins, RejectDuplicateKeys} {
+		p := New(WithFoldedKeys(), WithDuplicateKeys(policy))
+		for key, expected := range map[string]string{"ID": "1", "id": "2", "Id": "3", "iD": "1"} {
+			if policy == LastKeyWins && key == "iD" {
+				expected = "3"
+			}
+			if v, _, _, err := p.Get(data, key); err != nil || string(v) != expected {
+				t.Errorf("Policy %v: Get(%q) returned %s, %v; expected %s", policy, key, v, err, expected)
+			}
+		}
+		if v, err := p.Set(data, []byte(`4`), "id"); err != nil || string(v) != `{"ID":1,"id":4,"Id":3}` {
+			t.Errorf("Policy %v: Set returned %s, %v", policy, v, err)
+		}
+	}
+
+	// Only repeats of the same key are duplicates
+	reject := New(WithFoldedKeys(), WithDuplicateKeys(RejectDuplicateKeys))
+	rejectTests := []struct {
+		json     string
+		key      string
+		expected error
+	}{
+		{`{"ID":1,"id":2,"ID":3}`, "id", nil},
+		{`{"ID":1,"id":2,"id":3}`, "id", DuplicateKeyError},
+		{`{"ID":1,"Id":2,"ID":3}`, "id", DuplicateKeyError},
+		{`{"ID":1,"Id":2,"Id":3}`, "id", nil},
+	}
+	for _, test := range rejectTests {
+		if _, _, _, err := reject.Get([]byte(test.json), test.key); err != test.expected {
+			t.Errorf("Get(%s, %q) returned %v; expected %v", test.json, test.key, err, test.expected)
+		}
+	}
+}
+
+func TestPreservedIndentationParser(t *testing.T) {
+	p := New(WithPreservedIndentation())
+	pretty := "{\n  \"a\": 1,\n  \"list\": [\n    1\n  ],\n  \"obj\": {},\n  \"empty\": []\n}\n"
+
+	tests := []struct {
+		desc     string
+		json     string
+		path     []string
+		expected string
+	}{
+		{
+			desc:     "top-level member",
+			json:     pretty,
+			path:     []string{"b"},
+			expected: "{\n  \"a\": 1,\n  \"list\": [\n    1\n  ],\n  \"obj\": {},\n  \"empty\": [],\n  \"b\": true\n}\n",
+		},
+		{
+			desc:     "appended element",
+			json:     pretty,
+			path:     []string{"list", "[+]"},
+			expected: "{\n  \"a\": 1,\n  \"list\": [\n    1,\n    true\n  ],\n  \"obj\": {},\n  \"empty\": []\n}\n",
+		},
+		{
+			desc:     "prepended element",
+			json:     pretty,
+			path:     []string{"list", "[-]"},
+			expected: "{\n  \"a\": 1,\n  \"list\": [\n    true,\n    1\n  ],\n  \"obj\": {},\n  \"empty\": []\n}\n",
+		},
+		{
+			desc:     "padded element",
+			json:     pretty,
+			path:     []string{"list", "[2]"},
+			expected: "{\n  \"a\": 1,\n  \"list\": [\n    1,\n    null,\n    true\n  ],\n  \"obj\": {},\n  \"empty\": []\n}\n",
+		},
+		{
+			desc:     "member of an empty object",
+			json:     pretty,
+			path:     []string{"obj", "x"},
+			expected: "{\n  \"a\": 1,\n  \"list\": [\n    1\n  ],\n  \"obj\": {\n    \"x\": true\n  },\n  \"empty\": []\n}\n",
+		},
+		{
+			desc:     "element of an empty array",
+			json:     pretty,
+			path:     []string{"empty", "[1]"},
+			expected: "{\n  \"a\": 1,\n  \"list\": [\n    1\n  ],\n  \"obj\": {},\n  \"empty\": [\n    null,\n    true\n  ]\n}\n",
+		},
+		{
+			desc:     "created objects and arrays are indented",
+			json:     pretty,
+			path:     []string{"b", "c", "[0]"},
+			expected: "{\n  \"a\": 1,\n  \"list\": [\n    1\n  ],\n  \"obj\": {},\n  \"empty\": [],\n  \"b\": {\n    \"c\": [\n      true\n    ]\n  }\n}\n",
+		},
+		{
+			desc:     "tabs and no space after colons",
+			json:     "{\n\t\"a\":{\n\t\t\"b\":1\n\t}\n}",
+			path:     []string{"a", "c"},
+			expected: "{\n\t\"a\":{\n\t\t\"b\":1,\n\t\t\"c\":true\n\t}\n}",
+		},
+		{
+			desc:     "CRLF line breaks",
+			json:     "{\r\n  \"a\": 1\r\n}",
+			path:     []string{"b"},
+			expected: "{\r\n  \"a\": 1,\r\n  \"b\": true\r\n}",
+		},
+		{
+			desc:     "single line stays on one line",
+			json:     `{"a": 1, "b": {}}`,
+			path:     []string{"c"},
+			expected: `{"a": 1, "b": {}, "c": true}`,
+		},
+		{
+			desc:     "empty object of a single line document",
+			json:     `{"a": 1, "b": {}}`,
+			path:     []string{"b", "c"},
+			expected: `{"a": 1, "b": {"c":true}}`,
+		},
+		{
+			desc:     "replaced values fall back to plain Set",
+			json:     "{\n  \"a\": 1\n}",
+			path:     []string{"a", "b"},
+			expected: "{\n  \"a\": {\"b\":true}\n}",
+		},
+	}
+
+	for _, test := range tests {
+		if v, err := p.Set([]byte(test.json), []byte(`true`), test.path...); err != nil || string(v) != test.expected {
+			t.Errorf("%s: Set returned %q, %v; expected %q", test.desc, v, err, test.expected)
+		}
+	}
+}
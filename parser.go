@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"math"
 	"strconv"
-	"strings"
 )
 
 // Errors
@@ -19,6 +18,14 @@ var (
 	MalformedObjectError       = errors.New("Value looks like object, but can't find closing '}' symbol")
 	MalformedValueError        = errors.New("Value looks like Number/Boolean/None, but can't find its end: ',' or '}' symbol")
 	MalformedStringEscapeError = errors.New("Encountered an invalid escape sequence in a string")
+	DuplicateKeyError          = errors.New("Object contains duplicate key")
+	InvalidUTF8Error           = errors.New("Document is not valid UTF-8")
+	OverflowIntegerError       = errors.New("Value is number, but overflowed while parsing")
+	DecimalPrecisionError      = errors.New("Value is number, but has more decimal places than the requested scale")
+	EditConflictError          = errors.New("Edits conflict: one path continues another")
+	InvalidPatchError          = errors.New("Patch is not a valid JSON Patch document")
+	PatchTestFailedError       = errors.New("JSON Patch test operation failed")
+	UnsupportedValueError      = errors.New("Value has no JSON representation")
 )
 
 // How much stack space to allocate for unescaping JSON strings; if a string longer
@@ -36,71 +43,6 @@ func tokenEnd(data []byte) int {
 	return len(data)
 }
 
-func findTokenStart(data []byte, token byte) int {
-	for i := len(data) - 1; i >= 0; i-- {
-		switch data[i] {
-		case token:
-			return i
-		case '[', '{':
-			return 0
-		}
-	}
-
-	return 0
-}
-
-func findKeyStart(data []byte, key string) (int, error) {
-	i := 0
-	ln := len(data)
-	var stackbuf [unescapeStackBufSize]byte // stack-allocated array for allocation-free unescaping of small strings
-
-	if ku, err := Unescape(StringToBytes(key), stackbuf[:]); err == nil {
-		key = bytesToString(&ku)
-	}
-
-	for i < ln {
-		switch data[i] {
-		case '"':
-			i++
-			keyBegin := i
-
-			strEnd, keyEscaped := stringEnd(data[i:])
-			if strEnd == -1 {
-				break
-			}
-			i += strEnd
-			keyEnd := i - 1
-
-			valueOffset := nextToken(data[i:])
-			if valueOffset == -1 {
-				break
-			}
-
-			i += valueOffset
-
-			// if string is a key, and key level match
-			k := data[keyBegin:keyEnd]
-			// for unescape: if there are no escape sequences, this is cheap; if there are, it is a
-			// bit more expensive, but causes no allocations unless len(key) > unescapeStackBufSize
-			if keyEscaped {
-				if ku, err := Unescape(k, stackbuf[:]); err != nil {
-					break
-				} else {
-					k = ku
-				}
-			}
-
-			if data[i] == ':' && len(key) == len(k) && bytesToString(&k) == key {
-				return keyBegin - 1, nil
-			}
-
-		}
-		i++
-	}
-
-	return -1, KeyPathNotFoundError
-}
-
 func tokenStart(data []byte) int {
 	for i := len(data) - 1; i >= 0; i-- {
 		switch data[i] {
@@ -249,7 +191,7 @@ func searchKeys(data []byte, keys ...string) int {
 					keyUnesc = ku
 				}
 
-				if equalStr(&keyUnesc, keys[level-1]) {
+				if equalStr(&keyUnesc, segmentName(keys[level-1])) {
 					keyLevel++
 					// If we found all keys in path
 					if keyLevel == lk {
@@ -275,8 +217,13 @@ func searchKeys(data []byte, keys ...string) int {
 			}
 		case '[':
 			// If we want to get array element by index
-			if keyLevel == level && keys[level][0] == '[' {
-				aIdx, err := strconv.Atoi(keys[level][1 : len(keys[level])-1])
+			if keyLevel == level && len(keys[level]) > 0 && keys[level][0] == '[' {
+				// Append and prepend keys never name an existing element, and would make Atoi allocate its error
+				idxVal := keys[level][1 : len(keys[level])-1]
+				if idxVal == "+" || idxVal == "-" {
+					return -1
+				}
+				aIdx, err := strconv.Atoi(idxVal)
 				if err != nil {
 					return -1
 				}
@@ -329,6 +276,7 @@ func init() {
 	}
 }
 
+// sameTree reports whether path p1 and the keys p2 agree on their common levels.
 func sameTree(p1, p2 []string) bool {
 	minLen := len(p1)
 	if len(p2) < minLen {
@@ -336,7 +284,7 @@ func sameTree(p1, p2 []string) bool {
 	}
 
 	for pi_1, p_1 := range p1[:minLen] {
-		if p2[pi_1] != p_1 {
+		if p2[pi_1] != segmentName(p_1) {
 			return false
 		}
 	}
@@ -404,7 +352,7 @@ func EachKey(data []byte, cb func(int, []byte, ValueType, error), paths ...[]str
 
 					pathsBuf[level-1] = bytesToString(&keyUnesc)
 					for pi, p := range paths {
-						if len(p) != level || pathFlags&bitwiseFlags[pi+1] != 0 || !equalStr(&keyUnesc, p[level-1]) || !sameTree(p, pathsBuf[:level]) {
+						if len(p) != level || pathFlags&bitwiseFlags[pi+1] != 0 || !equalStr(&keyUnesc, segmentName(p[level-1])) || !sameTree(p, pathsBuf[:level]) {
 							continue
 						}
 
@@ -461,7 +409,7 @@ func EachKey(data []byte, cb func(int, []byte, ValueType, error), paths ...[]str
 			}
 
 			for pi, p := range paths {
-				if len(p) < level+1 || pathFlags&bitwiseFlags[pi+1] != 0 || p[level][0] != '[' || !sameTree(p, pathsBuf[:level]) {
+				if len(p) < level+1 || pathFlags&bitwiseFlags[pi+1] != 0 || len(p[level]) == 0 || p[level][0] != '[' || !sameTree(p, pathsBuf[:level]) {
 					continue
 				}
 
@@ -566,7 +514,7 @@ var (
 // Also returns the integer index value or 0 for +/- for convenience
 func isValidArrayIndex(key string) (isArray bool, idx int) {
 	idx = -1
-	if key[0] == '[' && key[len(key)-1] == ']' {
+	if len(key) > 1 && key[0] == '[' && key[len(key)-1] == ']' {
 		idxVal := key[1 : len(key)-1]
 		if idxVal == "+" || idxVal == "-" {
 			isArray = true
@@ -581,84 +529,100 @@ func isValidArrayIndex(key string) (isArray bool, idx int) {
 	return isArray, idx
 }
 
-// Creates the json component that will be inserted by Set(), including nested keys / arrays that need to be created.
+// Appends the json component that will be inserted by Set() to out, including nested keys / arrays that need to be created.
 // Also prefix/suffix with top level comma or {} based on provided bools.
-func createInsertComponent(keys []string, setValue []byte, startComma, endComma, isObject bool) []byte {
-	// If no keys, just return setValue with prefix/suffix comma as needed
+func appendInsertComponent(out []byte, keys []string, setValue []byte, startComma, endComma, isObject bool) []byte {
+	if startComma {
+		out = append(out, ',')
+	}
+
+	// If no keys, just write setValue with prefix/suffix comma as needed
 	if len(keys) == 0 {
-		if startComma {
-			value := make([]byte, len(setValue)+1)
-			value[0] = ','
-			copy(value[1:], setValue)
-			return value
-		} else if endComma {
-			value := make([]byte, len(setValue)+1)
-			copy(value, setValue)
-			value[len(value)-1] = ','
-			return value
-		} else {
-			return setValue
+		out = append(out, setValue...)
+		if endComma {
+			out = append(out, ',')
 		}
+		return out
 	}
 
-	// Otherwise use a buffer and iterate through keys
-	var buffer bytes.Buffer
-
-	// Initial prefixes, comma or top level array or object/first key
+	// Initial prefixes, top level array or object/first key
 	isArray, padCount := isValidArrayIndex(keys[0])
-	if startComma {
-		buffer.WriteString(",")
-	}
 	if isArray {
-		buffer.WriteString("[")
+		out = append(out, '[')
 		// pad array with nulls if non-zero numeric index
-		buffer.WriteString(strings.Repeat("null,", padCount))
+		out = appendNulls(out, padCount)
 	} else {
 		if isObject {
-			buffer.WriteString("{")
+			out = append(out, '{')
 		}
-		buffer.WriteString("\"")
-		buffer.WriteString(keys[0])
-		buffer.WriteString("\":")
+		out = AppendQuoted(out, segmentName(keys[0]))
+		out = append(out, ':')
 	}
 
 	// Iterate through remaining keys and create nested objects/arrays
 	for i := 1; i < len(keys); i++ {
 		isNestedArray, padCount := isValidArrayIndex(keys[i])
 		if isNestedArray {
-			buffer.WriteString("[")
-			buffer.WriteString(strings.Repeat("null,", padCount))
+			out = append(out, '[')
+			out = appendNulls(out, padCount)
 		} else {
-			buffer.WriteString("{\"")
-			buffer.WriteString(keys[i])
-			buffer.WriteString("\":")
+			out = append(out, '{')
+			out = AppendQuoted(out, segmentName(keys[i]))
+			out = append(out, ':')
 		}
 	}
 
 	// Write the actual set value
-	buffer.Write(setValue)
+	out = append(out, setValue...)
 
 	// Iterate backwards through keys to close objects/arrays
 	for i := len(keys) - 1; i > 0; i-- {
 		isInternalArray, _ := isValidArrayIndex(keys[i])
 		if isInternalArray {
-			buffer.WriteString("]")
+			out = append(out, ']')
 		} else {
-			buffer.WriteString("}")
+			out = append(out, '}')
 		}
 	}
 
 	// Suffix closing brackets / comma
 	if isArray {
-		buffer.WriteString("]")
+		out = append(out, ']')
 	} else if isObject {
-		buffer.WriteString("}")
+		out = append(out, '}')
 	}
 	if endComma {
-		buffer.WriteString(",")
+		out = append(out, ',')
+	}
+
+	return out
+}
+
+// appendNulls appends count null elements, each followed by a comma.
+func appendNulls(out []byte, count int) []byte {
+	for i := 0; i < count; i++ {
+		out = append(out, "null,"...)
+	}
+	return out
+}
+
+// appendInsert writes data with data[start:end] replaced by padCount ",null" elements and the component
+// Set inserts for keys into dst[:0]. dst is grown up front to what the component usually needs.
+func appendInsert(dst, data []byte, start, end, padCount int, keys []string, setValue []byte, startComma, endComma, isObject bool) []byte {
+	size := len(data) - (end - start) + len(setValue) + 2 + 5*padCount
+	for _, k := range keys {
+		size += len(k) + 5
+	}
+	if cap(dst) < size {
+		dst = make([]byte, 0, size)
 	}
 
-	return buffer.Bytes()
+	out := append(dst[:0], data[:start]...)
+	for i := 0; i < padCount; i++ {
+		out = append(out, ",null"...)
+	}
+	out = appendInsertComponent(out, keys, setValue, startComma, endComma, isObject)
+	return append(out, data[end:]...)
 }
 
 /*
@@ -666,67 +630,130 @@ func createInsertComponent(keys []string, setValue []byte, startComma, endComma,
 Del - Receives existing data structure, path to delete.
 
 Returns:
-`data` - return modified data
+`data` - return modified data. The input is never modified: the result is a new slice, or data itself when nothing is deleted
 
 */
 func Delete(data []byte, keys ...string) []byte {
-	lk := len(keys)
-	if lk == 0 {
+	value, _, _ := defaultParser.deleteE(data, keys...)
+	return value
+}
+
+// DeleteInPlace is Delete reusing the memory of data, which must not be used afterwards.
+// It does not allocate.
+func DeleteInPlace(data []byte, keys ...string) []byte {
+	return defaultParser.deleteInPlace(data, keys...)
+}
+
+// AppendDelete is Delete writing the result into dst[:0], which is grown only if it is too small,
+// so one buffer can be reused across documents. dst must not share memory with data.
+func AppendDelete(dst, data []byte, keys ...string) []byte {
+	return defaultParser.appendDelete(dst, data, keys...)
+}
+
+// DeleteE is Delete reporting what happened: found is false when keys do not exist, and err is set when
+// data is malformed on the path. In both cases data is returned unchanged.
+func DeleteE(data []byte, keys ...string) (value []byte, found bool, err error) {
+	return defaultParser.deleteE(data, keys...)
+}
+
+// deleteE implements DeleteE, and Delete by ignoring what it reports.
+func (p *Parser) deleteE(data []byte, keys ...string) (value []byte, found bool, err error) {
+	if len(keys) == 0 {
+		return data[:0:0], true, nil
+	}
+
+	start, end, err := p.deleteBounds(data, keys...)
+	if err == KeyPathNotFoundError {
+		return data, false, nil
+	} else if err != nil {
+		return data, false, err
+	}
+	return replaceSpan(data, start, end), true, nil
+}
+
+// deleteInPlace implements DeleteInPlace.
+func (p *Parser) deleteInPlace(data []byte, keys ...string) []byte {
+	if len(keys) == 0 {
 		return data[:0]
 	}
 
-	array := false
-	if len(keys[lk-1]) > 0 && string(keys[lk-1][0]) == "[" {
-		array = true
+	start, end, err := p.deleteBounds(data, keys...)
+	if err != nil {
+		return data
 	}
+	return append(data[:start], data[end:]...)
+}
 
-	var startOffset, keyOffset int
-	endOffset := len(data)
-	var err error
-	if !array {
-		if len(keys) > 1 {
-			_, _, startOffset, endOffset, err = internalGet(data, keys[:lk-1]...)
-			if err == KeyPathNotFoundError {
-				// problem parsing the data
-				return data
-			}
-		}
+// appendDelete implements AppendDelete.
+func (p *Parser) appendDelete(dst, data []byte, keys ...string) []byte {
+	if len(keys) == 0 {
+		return dst[:0]
+	}
 
-		keyOffset, err = findKeyStart(data[startOffset:endOffset], keys[lk-1])
-		if err == KeyPathNotFoundError {
-			// problem parsing the data
-			return data
-		}
-		keyOffset += startOffset
-		_, _, _, subEndOffset, _ := internalGet(data[startOffset:endOffset], keys[lk-1])
-		endOffset = startOffset + subEndOffset
-		tokEnd := tokenEnd(data[endOffset:])
-		tokStart := findTokenStart(data[:keyOffset], ","[0])
-
-		if data[endOffset+tokEnd] == ","[0] {
-			endOffset += tokEnd + 1
-		} else if data[endOffset+tokEnd] == "}"[0] && data[tokStart] == ","[0] {
-			keyOffset = tokStart
-		}
-	} else {
-		_, _, keyOffset, endOffset, err = internalGet(data, keys...)
-		if err == KeyPathNotFoundError {
-			// problem parsing the data
-			return data
-		}
+	start, end, err := p.deleteBounds(data, keys...)
+	if err != nil {
+		return append(dst[:0], data...)
+	}
+	return appendSpan(dst, data, start, end)
+}
 
-		tokEnd := tokenEnd(data[endOffset:])
-		tokStart := findTokenStart(data[:keyOffset], ","[0])
+// deleteBounds returns the part of data that deleting keys removes, or KeyPathNotFoundError if there is nothing to delete.
+func (p *Parser) deleteBounds(data []byte, keys ...string) (start, end int, err error) {
+	keyOffset, offset, endOffset, err := p.lookup(data, keys)
+	if err != nil {
+		return -1, -1, err
+	}
+	if keyOffset == -1 {
+		keyOffset = offset
+	}
 
-		if data[endOffset+tokEnd] == ","[0] {
-			endOffset += tokEnd + 1
-		} else if data[endOffset+tokEnd] == "]"[0] && data[tokStart] == ","[0] {
-			keyOffset = tokStart
+	start, end = memberBounds(data, keyOffset, endOffset)
+	return start, end, nil
+}
+
+// memberBounds extends data[keyOffset:endOffset], an object member or array element, over the comma
+// separating it from its neighbours and any whitespace before that comma, so that removing the result from
+// a well-formed object or array leaves it well-formed.
+func memberBounds(data []byte, keyOffset, endOffset int) (start, end int) {
+	off := nextToken(data[endOffset:])
+	if off == -1 {
+		return keyOffset, endOffset
+	}
+
+	switch data[endOffset+off] {
+	case ',':
+		endOffset += off + 1
+	case '}', ']':
+		// The last member takes the comma before it instead
+		if i := lastToken(data[:keyOffset]); i != -1 && data[i] == ',' {
+			keyOffset = i
 		}
 	}
 
-	data = append(data[:keyOffset], data[endOffset:]...)
-	return data
+	return keyOffset, endOffset
+}
+
+// replaceSpan returns a new slice holding data with data[start:end] replaced by insert.
+func replaceSpan(data []byte, start, end int, insert ...[]byte) []byte {
+	return appendSpan(nil, data, start, end, insert...)
+}
+
+// appendSpan writes data with data[start:end] replaced by insert into dst[:0], growing it to the exact
+// size needed if it is too small. dst must not share memory with data or insert.
+func appendSpan(dst, data []byte, start, end int, insert ...[]byte) []byte {
+	size := len(data) - (end - start)
+	for _, b := range insert {
+		size += len(b)
+	}
+
+	if cap(dst) < size {
+		dst = make([]byte, 0, size)
+	}
+	dst = append(dst[:0], data[:start]...)
+	for _, b := range insert {
+		dst = append(dst, b...)
+	}
+	return append(dst, data[end:]...)
 }
 
 /*
@@ -734,27 +761,76 @@ func Delete(data []byte, keys ...string) []byte {
 Set - Receives existing data structure, path to set, and data to set at that key.
 
 Returns:
-`value` - modified byte array
+`value` - modified byte array. It is always a new slice: neither data nor setValue are modified
 `err` - On any parsing error
 
 */
 func Set(data []byte, setValue []byte, keys ...string) (value []byte, err error) {
+	return defaultParser.appendSet(nil, data, setValue, keys...)
+}
+
+// AppendSet is Set writing the result into dst[:0], which is grown only if it is too small,
+// so one buffer can be reused across documents. dst must not share memory with data or setValue.
+func AppendSet(dst, data []byte, setValue []byte, keys ...string) (value []byte, err error) {
+	return defaultParser.appendSet(dst, data, setValue, keys...)
+}
+
+// SetString is Set with a Go string, which is quoted and escaped as needed.
+func SetString(data []byte, val string, keys ...string) (value []byte, err error) {
+	return Set(data, AppendQuoted(nil, val), keys...)
+}
+
+// SetInt is Set with a Go int64.
+func SetInt(data []byte, val int64, keys ...string) (value []byte, err error) {
+	return Set(data, strconv.AppendInt(nil, val, 10), keys...)
+}
+
+// SetFloat is Set with a Go float64, written in the shortest form that parses back to the same value.
+// NaN and infinities cannot be written as JSON and return UnsupportedValueError.
+func SetFloat(data []byte, val float64, keys ...string) (value []byte, err error) {
+	v, err := appendFloat(nil, val)
+	if err != nil {
+		return nil, err
+	}
+	return Set(data, v, keys...)
+}
+
+// SetBool is Set with a Go bool.
+func SetBool(data []byte, val bool, keys ...string) (value []byte, err error) {
+	if val {
+		return Set(data, trueLiteral, keys...)
+	}
+	return Set(data, falseLiteral, keys...)
+}
+
+// SetNull is Set with a null value.
+func SetNull(data []byte, keys ...string) (value []byte, err error) {
+	return Set(data, nullLiteral, keys...)
+}
+
+// appendSet implements Set, writing the result into dst[:0].
+func (p *Parser) appendSet(dst, data []byte, setValue []byte, keys ...string) (value []byte, err error) {
 	// ensure keys are set
 	if len(keys) == 0 {
 		return nil, KeyPathNotFoundError
 	}
 
-	_, _, startOffset, endOffset, err := internalGet(data, keys...)
+	_, _, startOffset, endOffset, err := p.internalGet(data, keys...)
 	if err != nil {
 		if err != KeyPathNotFoundError {
 			// problem parsing the data
 			return nil, err
 		}
+		if p.PreserveIndentation {
+			if value, ok, err := p.setIndented(dst, data, setValue, keys...); ok || err != nil {
+				return value, err
+			}
+		}
 		// full path doesnt exist
 		// does any subpath exist?
 		var depth int
 		for i := range keys {
-			_, _, start, end, sErr := internalGet(data, keys[:i+1]...)
+			_, _, start, end, sErr := p.internalGet(data, keys[:i+1]...)
 			if sErr != nil {
 				break
 			} else {
@@ -791,19 +867,9 @@ func Set(data []byte, setValue []byte, keys ...string) (value []byte, err error)
 			} else if isValidArray, _ := isValidArrayIndex(keys[depth]); data[startOffset] == '[' &&
 				data[startOffset+1+nextToken(data[startOffset+1:])] != ']' && isValidArray {
 				// if subpath is a non-empty array and next key is an array index, add to it
-				var arrayOffset int
-				var padString []byte
+				var arrayOffset, padCount int
 				idxVal := keys[depth][1 : len(keys[depth])-1]
-				idxNum, err := strconv.Atoi(idxVal)
-				if err == nil {
-					// Need to pad to get to idxNum'th element
-					elementCount := 0
-					ArrayEach(data[startOffset:endOffset], func(value []byte, dataType ValueType, offset int, err error) {
-						elementCount++
-						arrayOffset = offset + len(value)
-					})
-					padString = []byte(strings.Repeat(",null", idxNum-elementCount))
-				} else if idxVal == "+" {
+				if idxVal == "+" {
 					// Append to end of existing array
 					end := blockEnd(data[startOffset:endOffset], '[', ']')
 					if end != -1 {
@@ -815,6 +881,14 @@ func Set(data []byte, setValue []byte, keys ...string) (value []byte, err error)
 					arrayOffset = 1
 					endComma = true
 					startComma = false
+				} else if idxNum, err := strconv.Atoi(idxVal); err == nil {
+					// Need to pad to get to idxNum'th element
+					elementCount := 0
+					ArrayEach(data[startOffset:endOffset], func(value []byte, dataType ValueType, offset int, err error) {
+						elementCount++
+						arrayOffset = offset + len(value)
+					})
+					padCount = idxNum - elementCount
 				}
 				startOffset = startOffset + arrayOffset
 				depthOffset = startOffset
@@ -826,12 +900,7 @@ func Set(data []byte, setValue []byte, keys ...string) (value []byte, err error)
 				}
 
 				// build and insert final component including any padding, return
-				insertComponent := createInsertComponent(keys[depth:], setValue, startComma, endComma, object)
-				if len(padString) > 0 {
-					insertComponent = append(padString, insertComponent...)
-				}
-				value = append(data[:startOffset], append(insertComponent, data[depthOffset:]...)...)
-				return value, nil
+				return appendInsert(dst, data, startOffset, depthOffset, padCount, keys[depth:], setValue, startComma, endComma, object), nil
 			} else {
 				// if not existing object or array, just over-write subpath with a new object
 				startComma = false
@@ -840,17 +909,10 @@ func Set(data []byte, setValue []byte, keys ...string) (value []byte, err error)
 		} else {
 			startOffset = depthOffset
 		}
-		value = append(data[:startOffset], append(createInsertComponent(keys[depth:], setValue, startComma, endComma, object), data[depthOffset:]...)...)
+		value = appendInsert(dst, data, startOffset, depthOffset, 0, keys[depth:], setValue, startComma, endComma, object)
 	} else {
 		// path currently exists
-		startComponent := data[:startOffset]
-		endComponent := data[endOffset:]
-
-		value = make([]byte, len(startComponent)+len(endComponent)+len(setValue))
-		newEndOffset := startOffset + len(setValue)
-		copy(value[0:startOffset], startComponent)
-		copy(value[startOffset:newEndOffset], setValue)
-		copy(value[newEndOffset:], endComponent)
+		value = appendSpan(dst, data, startOffset, endOffset, setValue)
 	}
 	return value, nil
 }
@@ -967,6 +1029,11 @@ func internalGet(data []byte, keys ...string) (value []byte, dataType ValueType,
 
 // ArrayEach is used when iterating arrays, accepts a callback function with the same return arguments as `Get`.
 func ArrayEach(data []byte, cb func(value []byte, dataType ValueType, offset int, err error), keys ...string) (offset int, err error) {
+	return arrayEach(data, cb, false, keys...)
+}
+
+// arrayEach implements ArrayEach. With inPlace set, string values are unescaped into data before being passed on.
+func arrayEach(data []byte, cb func(value []byte, dataType ValueType, offset int, err error), inPlace bool, keys ...string) (offset int, err error) {
 	if len(data) == 0 {
 		return -1, MalformedObjectError
 	}
@@ -1016,7 +1083,12 @@ func ArrayEach(data []byte, cb func(value []byte, dataType ValueType, offset int
 		}
 
 		if t != NotExist {
-			cb(v, t, offset+o-len(v), e)
+			vOffset := offset + o - len(v)
+			if v, e = unescapeValueInPlace(v, t, inPlace); e != nil {
+				cb(nil, t, vOffset, e)
+				return vOffset, e
+			}
+			cb(v, t, vOffset, e)
 		}
 
 		if e != nil {
@@ -1045,9 +1117,25 @@ func ArrayEach(data []byte, cb func(value []byte, dataType ValueType, offset int
 	return offset, nil
 }
 
+// unescapeValueInPlace unescapes string values into their own bytes when inPlace is set, and returns other values as they are.
+func unescapeValueInPlace(value []byte, dataType ValueType, inPlace bool) ([]byte, error) {
+	if !inPlace || dataType != String {
+		return value, nil
+	}
+	return UnescapeInPlace(value)
+}
+
 // ObjectEach iterates over the key-value pairs of a JSON object, invoking a given callback for each such entry
 func ObjectEach(data []byte, callback func(key []byte, value []byte, dataType ValueType, offset int) error, keys ...string) (err error) {
-	var stackbuf [unescapeStackBufSize]byte // stack-allocated array for allocation-free unescaping of small strings
+	return objectEach(data, callback, false, keys...)
+}
+
+// objectEach implements ObjectEach. With inPlace set, keys and string values are unescaped into data
+// rather than into a buffer of their own.
+func objectEach(data []byte, callback func(key []byte, value []byte, dataType ValueType, offset int) error, inPlace bool, keys ...string) (err error) {
+	// Buffer for unescaping small keys. Keys are passed to the callback, so it cannot live on the stack;
+	// it is allocated on the first escaped key, and never when unescaping in place.
+	var keybuf *[unescapeStackBufSize]byte
 	offset := 0
 
 	// Descend to the desired key, if requested
@@ -1101,7 +1189,14 @@ func ObjectEach(data []byte, callback func(key []byte, value []byte, dataType Va
 
 		// Unescape the string if needed
 		if keyEscaped {
-			if keyUnescaped, err := Unescape(key, stackbuf[:]); err != nil {
+			out := key[:0]
+			if !inPlace {
+				if keybuf == nil {
+					keybuf = new([unescapeStackBufSize]byte)
+				}
+				out = keybuf[:]
+			}
+			if keyUnescaped, err := Unescape(key, out); err != nil {
 				return MalformedStringEscapeError
 			} else {
 				key = keyUnescaped
@@ -1120,6 +1215,8 @@ func ObjectEach(data []byte, callback func(key []byte, value []byte, dataType Va
 		// Step 3: find the associated value, then invoke the callback
 		if value, valueType, off, err := Get(data[offset:]); err != nil {
 			return err
+		} else if value, err = unescapeValueInPlace(value, valueType, inPlace); err != nil {
+			return err
 		} else if err := callback(key, value, valueType, offset+off); err != nil { // Invoke the callback here!
 			return err
 		} else {
@@ -1155,7 +1252,74 @@ func ObjectEach(data []byte, callback func(key []byte, value []byte, dataType Va
 // GetUnsafeString returns the value retrieved by `Get`, use creates string without memory allocation by mapping string to slice memory. It does not handle escape symbols.
 func GetUnsafeString(data []byte, keys ...string) (val string, err error) {
 	v, _, _, e := Get(data, keys...)
+	return unsafeStringValue(v, e)
+}
 
+// GetString returns the value retrieved by `Get`, cast to a string if possible, trying to properly handle escape and utf8 symbols
+// If key data type do not match, it will return an error.
+func GetString(data []byte, keys ...string) (val string, err error) {
+	v, t, _, e := Get(data, keys...)
+	return stringValue(v, t, e)
+}
+
+// GetStringView returns the value retrieved by `Get` as a string, properly handling escape and utf8 symbols like `GetString`,
+// but without allocating in the common cases. Strings without escapes are returned as an unsafe view of data, like `GetUnsafeString`;
+// other strings are unescaped into buf, and the result is an unsafe view of buf. Only if buf is too small is a new buffer allocated.
+// The result is valid only as long as data and buf are neither modified nor reused.
+// If key data type do not match, it will return an error.
+func GetStringView(data []byte, buf []byte, keys ...string) (val string, err error) {
+	v, t, _, e := Get(data, keys...)
+	return stringViewValue(v, t, e, buf)
+}
+
+// GetFloat returns the value retrieved by `Get`, cast to a float64 if possible.
+// The offset is the same as in `Get`.
+// If key data type do not match, it will return an error.
+func GetFloat(data []byte, keys ...string) (val float64, err error) {
+	v, t, _, e := Get(data, keys...)
+	return floatValue(v, t, e)
+}
+
+// GetInt returns the value retrieved by `Get`, cast to a int64 if possible.
+// If key data type do not match, it will return an error.
+func GetInt(data []byte, keys ...string) (val int64, err error) {
+	v, t, _, e := Get(data, keys...)
+	return intValue(v, t, e)
+}
+
+// GetInt32 returns the value retrieved by `Get`, cast to a int32 if possible.
+// If key data type do not match, or the value does not fit, it will return an error.
+func GetInt32(data []byte, keys ...string) (val int32, err error) {
+	v, t, _, e := Get(data, keys...)
+	return int32Value(v, t, e)
+}
+
+// GetUint64 returns the value retrieved by `Get`, cast to a uint64 if possible.
+// Use it for identifiers such as Snowflake IDs that may not fit in an int64.
+// If key data type do not match, or the value does not fit, it will return an error.
+func GetUint64(data []byte, keys ...string) (val uint64, err error) {
+	v, t, _, e := Get(data, keys...)
+	return uint64Value(v, t, e)
+}
+
+// GetUint32 returns the value retrieved by `Get`, cast to a uint32 if possible.
+// If key data type do not match, or the value does not fit, it will return an error.
+func GetUint32(data []byte, keys ...string) (val uint32, err error) {
+	v, t, _, e := Get(data, keys...)
+	return uint32Value(v, t, e)
+}
+
+// GetBoolean returns the value retrieved by `Get`, cast to a bool if possible.
+// The offset is the same as in `Get`.
+// If key data type do not match, it will return error.
+func GetBoolean(data []byte, keys ...string) (val bool, err error) {
+	v, t, _, e := Get(data, keys...)
+	return booleanValue(v, t, e)
+}
+
+// The functions below convert what Get returned for the typed getters, which the Parser methods share.
+
+func unsafeStringValue(v []byte, e error) (string, error) {
 	if e != nil {
 		return "", e
 	}
@@ -1163,11 +1327,7 @@ func GetUnsafeString(data []byte, keys ...string) (val string, err error) {
 	return bytesToString(&v), nil
 }
 
-// GetString returns the value retrieved by `Get`, cast to a string if possible, trying to properly handle escape and utf8 symbols
-// If key data type do not match, it will return an error.
-func GetString(data []byte, keys ...string) (val string, err error) {
-	v, t, _, e := Get(data, keys...)
-
+func stringValue(v []byte, t ValueType, e error) (string, error) {
 	if e != nil {
 		return "", e
 	}
@@ -1184,12 +1344,23 @@ func GetString(data []byte, keys ...string) (val string, err error) {
 	return ParseString(v)
 }
 
-// GetFloat returns the value retrieved by `Get`, cast to a float64 if possible.
-// The offset is the same as in `Get`.
-// If key data type do not match, it will return an error.
-func GetFloat(data []byte, keys ...string) (val float64, err error) {
-	v, t, _, e := Get(data, keys...)
+func stringViewValue(v []byte, t ValueType, e error, buf []byte) (string, error) {
+	if e != nil {
+		return "", e
+	}
+
+	if t != String {
+		return "", fmt.Errorf("Value is not a string: %s", string(v))
+	}
+
+	if u, err := Unescape(v, buf); err != nil {
+		return "", MalformedValueError
+	} else {
+		return bytesToString(&u), nil
+	}
+}
 
+func floatValue(v []byte, t ValueType, e error) (float64, error) {
 	if e != nil {
 		return 0, e
 	}
@@ -1201,11 +1372,7 @@ func GetFloat(data []byte, keys ...string) (val float64, err error) {
 	return ParseFloat(v)
 }
 
-// GetInt returns the value retrieved by `Get`, cast to a int64 if possible.
-// If key data type do not match, it will return an error.
-func GetInt(data []byte, keys ...string) (val int64, err error) {
-	v, t, _, e := Get(data, keys...)
-
+func intValue(v []byte, t ValueType, e error) (int64, error) {
 	if e != nil {
 		return 0, e
 	}
@@ -1217,12 +1384,47 @@ func GetInt(data []byte, keys ...string) (val int64, err error) {
 	return ParseInt(v)
 }
 
-// GetBoolean returns the value retrieved by `Get`, cast to a bool if possible.
-// The offset is the same as in `Get`.
-// If key data type do not match, it will return error.
-func GetBoolean(data []byte, keys ...string) (val bool, err error) {
-	v, t, _, e := Get(data, keys...)
+func int32Value(v []byte, t ValueType, e error) (int32, error) {
+	i, e := intValue(v, t, e)
 
+	if e != nil {
+		return 0, e
+	}
+
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, OverflowIntegerError
+	}
+
+	return int32(i), nil
+}
+
+func uint64Value(v []byte, t ValueType, e error) (uint64, error) {
+	if e != nil {
+		return 0, e
+	}
+
+	if t != Number {
+		return 0, fmt.Errorf("Value is not a number: %s", string(v))
+	}
+
+	return ParseUint(v)
+}
+
+func uint32Value(v []byte, t ValueType, e error) (uint32, error) {
+	u, e := uint64Value(v, t, e)
+
+	if e != nil {
+		return 0, e
+	}
+
+	if u > math.MaxUint32 {
+		return 0, OverflowIntegerError
+	}
+
+	return uint32(u), nil
+}
+
+func booleanValue(v []byte, t ValueType, e error) (bool, error) {
 	if e != nil {
 		return false, e
 	}
@@ -1267,8 +1469,30 @@ func ParseFloat(b []byte) (float64, error) {
 
 // ParseInt parses a Number ValueType into a Go int64
 func ParseInt(b []byte) (int64, error) {
-	if v, ok := parseInt(b); !ok {
+	if v, ok, overflow := parseInt(b); !ok {
+		if overflow {
+			return 0, OverflowIntegerError
+		}
+		return 0, MalformedValueError
+	} else {
+		return v, nil
+	}
+}
+
+// ParseUint parses a Number ValueType into a Go uint64. Negative numbers other than -0 do not fit and return OverflowIntegerError.
+func ParseUint(b []byte) (uint64, error) {
+	neg := len(b) > 0 && b[0] == '-'
+	if neg {
+		b = b[1:]
+	}
+
+	if v, ok, overflow := parseUint(b); !ok {
+		if overflow {
+			return 0, OverflowIntegerError
+		}
 		return 0, MalformedValueError
+	} else if neg && v != 0 {
+		return 0, OverflowIntegerError
 	} else {
 		return v, nil
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	_ "fmt"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -137,11 +138,53 @@ var deleteTests = []DeleteTest{
 		path: []string{"a"},
 		data: `{ `,
 	},
-	{ // This test dels the key instead of returning a parse error, as checking for the malformed JSON would reduce performance (this is not ideal)
+	{ // Malformed input on the path is returned unchanged rather than partly deleted
 		desc: "malformed 'colon chain', delete b",
 		json: `{"a":"b":"c"}`,
 		path: []string{"b"},
-		data: `{"a":}`,
+		data: `{"a":"b":"c"}`,
+	},
+	{
+		desc: "key also used by a nested object",
+		json: `{"x":{"b":1},"b":2}`,
+		path: []string{"b"},
+		data: `{"x":{"b":1}}`,
+	},
+	{
+		desc: "nested key also used by a deeper object",
+		json: `{"a":{"x":{"b":1},"b":2}}`,
+		path: []string{"a", "b"},
+		data: `{"a":{"x":{"b":1}}}`,
+	},
+	{
+		desc: "whitespace before the comma after a member",
+		json: `{"a":1 ,"b":2}`,
+		path: []string{"a"},
+		data: `{"b":2}`,
+	},
+	{
+		desc: "whitespace around the comma before the last member",
+		json: `{"a":1 , "b":2 }`,
+		path: []string{"b"},
+		data: `{"a":1  }`,
+	},
+	{
+		desc: "whitespace before the closing brace",
+		json: `{"a":1,"b":2 }`,
+		path: []string{"b"},
+		data: `{"a":1 }`,
+	},
+	{
+		desc: "whitespace before the comma after an element",
+		json: `[1 , 2]`,
+		path: []string{"[0]"},
+		data: `[ 2]`,
+	},
+	{
+		desc: "whitespace before the closing bracket",
+		json: "[1,\n  2\n]",
+		path: []string{"[1]"},
+		data: "[1\n]",
 	},
 }
 
@@ -426,6 +469,30 @@ var setTests = []SetTest{
 		isFound: true,
 		data:    `{"top":["value",{"middle":["value2"]}]}`,
 	},
+	{
+		desc:    "new keys are escaped",
+		json:    `{"top":"value"}`,
+		path:    []string{"a\"b", "c\\d\n"},
+		setData: `1`,
+		isFound: true,
+		data:    `{"top":"value","a\"b":{"c\\d\n":1}}`,
+	},
+	{
+		desc:    "new keys are escaped in empty objects and arrays",
+		json:    `{"top":[]}`,
+		path:    []string{"top", "[0]", "<\t>"},
+		setData: `1`,
+		isFound: true,
+		data:    `{"top":[{"<\t>":1}]}`,
+	},
+	{
+		desc:    "existing escaped keys are found",
+		json:    `{"a\"b":1}`,
+		path:    []string{"a\"b"},
+		setData: `2`,
+		isFound: true,
+		data:    `{"a\"b":2}`,
+	},
 }
 
 var getTests = []GetTest{
@@ -866,6 +933,91 @@ var getIntTests = []GetTest{
 		isFound: true,
 		data:    int64(1),
 	},
+	{
+		desc:  `overflowing integer`,
+		json:  `{"c": 99999999999999999999}`,
+		path:  []string{"c"},
+		isErr: true,
+	},
+}
+
+var getInt32Tests = []GetTest{
+	{
+		desc:    `read minimum int32`,
+		json:    `{"c": -2147483648}`,
+		path:    []string{"c"},
+		isFound: true,
+		data:    int32(-2147483648),
+	},
+	{
+		desc:  `int64 out of int32 range`,
+		json:  `{"c": 2147483648}`,
+		path:  []string{"c"},
+		isErr: true,
+	},
+	{
+		desc:  `string value`,
+		json:  `{"c": "1"}`,
+		path:  []string{"c"},
+		isErr: true,
+	},
+}
+
+var getUint64Tests = []GetTest{
+	{
+		desc:    `read snowflake id above int64 range`,
+		json:    `{"id": 18446744073709551615}`,
+		path:    []string{"id"},
+		isFound: true,
+		data:    uint64(18446744073709551615),
+	},
+	{
+		desc:    `read negative zero`,
+		json:    `{"id": -0}`,
+		path:    []string{"id"},
+		isFound: true,
+		data:    uint64(0),
+	},
+	{
+		desc:  `negative value`,
+		json:  `{"id": -1}`,
+		path:  []string{"id"},
+		isErr: true,
+	},
+	{
+		desc:  `overflowing value`,
+		json:  `{"id": 18446744073709551616}`,
+		path:  []string{"id"},
+		isErr: true,
+	},
+	{
+		desc:  `fractional value`,
+		json:  `{"id": 1.5}`,
+		path:  []string{"id"},
+		isErr: true,
+	},
+	{
+		desc:    `missing key`,
+		json:    `{"id": 1}`,
+		path:    []string{"other"},
+		isFound: false,
+	},
+}
+
+var getUint32Tests = []GetTest{
+	{
+		desc:    `read maximum uint32`,
+		json:    `{"c": 4294967295}`,
+		path:    []string{"c"},
+		isFound: true,
+		data:    uint32(4294967295),
+	},
+	{
+		desc:  `uint64 out of uint32 range`,
+		json:  `{"c": 4294967296}`,
+		path:  []string{"c"},
+		isErr: true,
+	},
 }
 
 var getFloatTests = []GetTest{
@@ -1149,6 +1301,75 @@ func TestSet(t *testing.T) {
 	)
 }
 
+func TestTypedSetters(t *testing.T) {
+	tests := []struct {
+		desc     string
+		set      func(data []byte) ([]byte, error)
+		expected string
+		err      error
+	}{
+		{
+			desc:     "string with escapes",
+			set:      func(data []byte) ([]byte, error) { return SetString(data, "a\"b\\c\n\x01", "s") },
+			expected: `{"a":1,"s":"a\"b\\c\n\u0001"}`,
+		},
+		{
+			desc:     "int",
+			set:      func(data []byte) ([]byte, error) { return SetInt(data, -9223372036854775808, "a") },
+			expected: `{"a":-9223372036854775808}`,
+		},
+		{
+			desc:     "integral float",
+			set:      func(data []byte) ([]byte, error) { return SetFloat(data, 100, "a") },
+			expected: `{"a":100}`,
+		},
+		{
+			desc:     "shortest round-trip float",
+			set:      func(data []byte) ([]byte, error) { return SetFloat(data, math.Nextafter(0.3, 1), "a") },
+			expected: `{"a":0.30000000000000004}`,
+		},
+		{
+			desc:     "small float",
+			set:      func(data []byte) ([]byte, error) { return SetFloat(data, 1e-7, "a") },
+			expected: `{"a":1e-7}`,
+		},
+		{
+			desc:     "large float",
+			set:      func(data []byte) ([]byte, error) { return SetFloat(data, -1.5e300, "a") },
+			expected: `{"a":-1.5e+300}`,
+		},
+		{
+			desc: "NaN",
+			set:  func(data []byte) ([]byte, error) { return SetFloat(data, math.NaN(), "a") },
+			err:  UnsupportedValueError,
+		},
+		{
+			desc: "infinity",
+			set:  func(data []byte) ([]byte, error) { return SetFloat(data, math.Inf(1), "a") },
+			err:  UnsupportedValueError,
+		},
+		{
+			desc:     "bool",
+			set:      func(data []byte) ([]byte, error) { return SetBool(data, true, "b", "[0]") },
+			expected: `{"a":1,"b":[true]}`,
+		},
+		{
+			desc:     "null",
+			set:      func(data []byte) ([]byte, error) { return SetNull(data, "a") },
+			expected: `{"a":null}`,
+		},
+	}
+
+	for _, test := range tests {
+		value, err := test.set([]byte(`{"a":1}`))
+		if err != test.err {
+			t.Errorf("%s: expected error %v, obtained %v", test.desc, test.err, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("%s: expected %s, obtained %s", test.desc, test.expected, value)
+		}
+	}
+}
+
 func TestDelete(t *testing.T) {
 	runDeleteTests(t, "Delete()", deleteTests,
 		func(test DeleteTest) interface{} {
@@ -1161,6 +1382,170 @@ func TestDelete(t *testing.T) {
 	)
 }
 
+// sharedBuffer returns s in a slice with spare capacity, followed by a copy of the whole backing array
+// to compare against later.
+func sharedBuffer(s string) (buf []byte, backing []byte) {
+	buf = make([]byte, len(s), len(s)+64)
+	copy(buf, s)
+	for i := len(s); i < cap(buf); i++ {
+		buf[:cap(buf)][i] = 'x'
+	}
+	return buf, append([]byte(nil), buf[:cap(buf)]...)
+}
+
+func TestAppendSet(t *testing.T) {
+	// A buffer left dirty by the previous test, as when one is reused across messages
+	buf := make([]byte, 0, 16)
+	runSetTests(t, "AppendSet()", setTests,
+		func(test SetTest) (value interface{}, dataType ValueType, err error) {
+			v, err := AppendSet(buf, []byte(test.json), []byte(test.setData), test.path...)
+			if err == nil {
+				buf = v
+			}
+			return v, Unknown, err
+		},
+		func(test SetTest, value interface{}) (bool, interface{}) {
+			expected := []byte(test.data.(string))
+			return bytes.Equal(expected, value.([]byte)), expected
+		},
+	)
+
+	allocTests := []struct {
+		desc     string
+		path     []string
+		expected string
+	}{
+		{"replacing a value", []string{"a", "b", "[1]"}, `{"a":{"b":[1,3]},"c":"d"}`},
+		{"inserting a key", []string{"a", "e", "f"}, `{"a":{"b":[1,2],"e":{"f":3}},"c":"d"}`},
+		{"appending to an array", []string{"a", "b", "[+]"}, `{"a":{"b":[1,2,3]},"c":"d"}`},
+		{"padding an array", []string{"a", "b", "[4]"}, `{"a":{"b":[1,2,null,null,3]},"c":"d"}`},
+	}
+	data := []byte(`{"a":{"b":[1,2]},"c":"d"}`)
+	dst := make([]byte, 0, 256)
+	for _, test := range allocTests {
+		allocs := testing.AllocsPerRun(100, func() {
+			if v, err := AppendSet(dst, data, []byte(`3`), test.path...); err != nil || string(v) != test.expected {
+				t.Fatalf("AppendSet %s returned %s, %v", test.desc, v, err)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("AppendSet %s into a large enough buffer allocated %v times", test.desc, allocs)
+		}
+	}
+}
+
+func TestDeleteE(t *testing.T) {
+	runDeleteTests(t, "DeleteE()", deleteTests,
+		func(test DeleteTest) interface{} {
+			value, _, _ := DeleteE([]byte(test.json), test.path...)
+			return value
+		},
+		func(test DeleteTest, value interface{}) (bool, interface{}) {
+			expected := []byte(test.data.(string))
+			return bytes.Equal(expected, value.([]byte)), expected
+		},
+	)
+
+	tests := []struct {
+		json  string
+		path  []string
+		found bool
+		err   error
+	}{
+		{json: `{"a":{"b":1}}`, path: []string{"a", "b"}, found: true},
+		{json: `{"a":[1,2]}`, path: []string{"a", "[1]"}, found: true},
+		{json: `{"a":1}`, found: true},
+		{json: `{"a":{"b":1}}`, path: []string{"a", "c"}},
+		{json: `{"a":[1]}`, path: []string{"a", "[1]"}},
+		{json: `{"a":1}`, path: []string{"a", "b"}},
+		{json: `{"a":"b":"c"}`, path: []string{"b"}, err: MalformedJsonError},
+		{json: `{"a":{"b":1`, path: []string{"a", "c"}, err: MalformedJsonError},
+	}
+	for _, test := range tests {
+		_, found, err := DeleteE([]byte(test.json), test.path...)
+		if found != test.found || (err == nil) != (test.err == nil) {
+			t.Errorf("DeleteE(%s, %q) returned %t, %v; expected %t, %v", test.json, test.path, found, err, test.found, test.err)
+		}
+	}
+}
+
+func TestAppendDelete(t *testing.T) {
+	buf := make([]byte, 0, 16)
+	runDeleteTests(t, "AppendDelete()", deleteTests,
+		func(test DeleteTest) interface{} {
+			buf = AppendDelete(buf, []byte(test.json), test.path...)
+			return buf
+		},
+		func(test DeleteTest, value interface{}) (bool, interface{}) {
+			expected := []byte(test.data.(string))
+			return bytes.Equal(expected, value.([]byte)), expected
+		},
+	)
+
+	data := []byte(`{"a":{"b":[1,2]},"c":"d"}`)
+	dst := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		if v := AppendDelete(dst, data, "a", "b"); string(v) != `{"a":{},"c":"d"}` {
+			t.Fatalf("AppendDelete returned %s", v)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("AppendDelete into a large enough buffer allocated %v times", allocs)
+	}
+}
+
+func TestSetDoesNotModifyInput(t *testing.T) {
+	for _, test := range setTests {
+		data, dataBacking := sharedBuffer(test.json)
+		setValue, setValueBacking := sharedBuffer(test.setData)
+
+		Set(data, setValue, test.path...)
+
+		if !bytes.Equal(data[:cap(data)], dataBacking) {
+			t.Errorf("Set test '%s' modified the backing array of its input: %s", test.desc, data[:cap(data)])
+		}
+		if !bytes.Equal(setValue[:cap(setValue)], setValueBacking) {
+			t.Errorf("Set test '%s' modified the backing array of its value: %s", test.desc, setValue[:cap(setValue)])
+		}
+	}
+}
+
+func TestDeleteDoesNotModifyInput(t *testing.T) {
+	for _, test := range deleteTests {
+		data, backing := sharedBuffer(test.json)
+
+		Delete(data, test.path...)
+
+		if !bytes.Equal(data[:cap(data)], backing) {
+			t.Errorf("Delete test '%s' modified the backing array of its input: %s", test.desc, data[:cap(data)])
+		}
+	}
+}
+
+func TestDeleteInPlace(t *testing.T) {
+	runDeleteTests(t, "DeleteInPlace()", deleteTests,
+		func(test DeleteTest) interface{} {
+			return DeleteInPlace([]byte(test.json), test.path...)
+		},
+		func(test DeleteTest, value interface{}) (bool, interface{}) {
+			expected := []byte(test.data.(string))
+			return bytes.Equal(expected, value.([]byte)), expected
+		},
+	)
+
+	orig := []byte(`{"a":1,"b":{"c":[1,2,3]},"d":"e"}`)
+	buf := make([]byte, len(orig))
+	allocs := testing.AllocsPerRun(100, func() {
+		copy(buf, orig)
+		if v := DeleteInPlace(buf, "b", "c", "[1]"); string(v) != `{"a":1,"b":{"c":[1,3]},"d":"e"}` {
+			t.Fatalf("DeleteInPlace returned %s", v)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("DeleteInPlace allocated %v times", allocs)
+	}
+}
+
 func TestGet(t *testing.T) {
 	runGetTests(t, "Get()", getTests,
 		func(test GetTest) (value interface{}, dataType ValueType, err error) {
@@ -1187,6 +1572,49 @@ func TestGetString(t *testing.T) {
 	)
 }
 
+func TestGetStringView(t *testing.T) {
+	runGetTests(t, "GetStringView()", getStringTests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetStringView([]byte(test.json), make([]byte, 0, 8), test.path...)
+			return value, String, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(string)
+			return expected == value.(string), expected
+		},
+	)
+}
+
+func TestGetStringViewNoAlloc(t *testing.T) {
+	data := []byte(`{"plain": "hello world", "escaped": "tab\tand \u00b0", "num": 1}`)
+	buf := make([]byte, 0, 64)
+
+	var plain, escaped string
+	allocs := testing.AllocsPerRun(100, func() {
+		plain, _ = GetStringView(data, buf, "plain")
+		escaped, _ = GetStringView(data, buf, "escaped")
+	})
+
+	if allocs != 0 {
+		t.Errorf("GetStringView allocated %v times", allocs)
+	}
+	if plain != "hello world" || escaped != "tab\tand \u00b0" {
+		t.Errorf("GetStringView returned %q and %q", plain, escaped)
+	}
+
+	// A buffer that is too small still gives the right result
+	if v, err := GetStringView(data, nil, "escaped"); err != nil || v != "tab\tand \u00b0" {
+		t.Errorf("GetStringView with nil buffer returned %q, %v", v, err)
+	}
+
+	if _, err := GetStringView(data, buf, "num"); err == nil {
+		t.Errorf("GetStringView on a number should return an error")
+	}
+	if _, err := GetStringView([]byte(`{"bad": "\x"}`), buf, "bad"); err == nil {
+		t.Errorf("GetStringView on an invalid escape should return an error")
+	}
+}
+
 func TestGetInt(t *testing.T) {
 	runGetTests(t, "GetInt()", getIntTests,
 		func(test GetTest) (value interface{}, dataType ValueType, err error) {
@@ -1200,6 +1628,45 @@ func TestGetInt(t *testing.T) {
 	)
 }
 
+func TestGetInt32(t *testing.T) {
+	runGetTests(t, "GetInt32()", getInt32Tests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetInt32([]byte(test.json), test.path...)
+			return value, Number, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(int32)
+			return expected == value.(int32), expected
+		},
+	)
+}
+
+func TestGetUint64(t *testing.T) {
+	runGetTests(t, "GetUint64()", getUint64Tests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetUint64([]byte(test.json), test.path...)
+			return value, Number, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(uint64)
+			return expected == value.(uint64), expected
+		},
+	)
+}
+
+func TestGetUint32(t *testing.T) {
+	runGetTests(t, "GetUint32()", getUint32Tests,
+		func(test GetTest) (value interface{}, dataType ValueType, err error) {
+			value, err = GetUint32([]byte(test.json), test.path...)
+			return value, Number, err
+		},
+		func(test GetTest, value interface{}) (bool, interface{}) {
+			expected := test.data.(uint32)
+			return expected == value.(uint32), expected
+		},
+	)
+}
+
 func TestGetFloat(t *testing.T) {
 	runGetTests(t, "GetFloat()", getFloatTests,
 		func(test GetTest) (value interface{}, dataType ValueType, err error) {
@@ -1689,6 +2156,83 @@ func TestParseFloat(t *testing.T) {
 	)
 }
 
+var parseIntTest = []ParseTest{
+	{
+		in:     "-9223372036854775808",
+		intype: Number,
+		out:    int64(-9223372036854775808),
+	},
+	{
+		in:     "99999999999999999999",
+		intype: Number,
+		isErr:  true,
+	},
+	{
+		in:     "1.0",
+		intype: Number,
+		isErr:  true,
+	},
+}
+
+func TestParseInt(t *testing.T) {
+	runParseTests(t, "ParseInt()", parseIntTest,
+		func(test ParseTest) (value interface{}, err error) {
+			return ParseInt([]byte(test.in))
+		},
+		func(test ParseTest, obtained interface{}) (bool, interface{}) {
+			expected := test.out.(int64)
+			return obtained.(int64) == expected, expected
+		},
+	)
+
+	if _, err := ParseInt([]byte("99999999999999999999")); err != OverflowIntegerError {
+		t.Errorf("ParseInt() expected OverflowIntegerError, obtained %v", err)
+	}
+	if _, err := ParseInt([]byte("1x")); err != MalformedValueError {
+		t.Errorf("ParseInt() expected MalformedValueError, obtained %v", err)
+	}
+}
+
+var parseUintTest = []ParseTest{
+	{
+		in:     "18446744073709551615",
+		intype: Number,
+		out:    uint64(18446744073709551615),
+	},
+	{
+		in:     "-0",
+		intype: Number,
+		out:    uint64(0),
+	},
+	{
+		in:     "-1",
+		intype: Number,
+		isErr:  true,
+	},
+	{
+		in:     "18446744073709551616",
+		intype: Number,
+		isErr:  true,
+	},
+	{
+		in:     "-",
+		intype: Number,
+		isErr:  true,
+	},
+}
+
+func TestParseUint(t *testing.T) {
+	runParseTests(t, "ParseUint()", parseUintTest,
+		func(test ParseTest) (value interface{}, err error) {
+			return ParseUint([]byte(test.in))
+		},
+		func(test ParseTest, obtained interface{}) (bool, interface{}) {
+			expected := test.out.(uint64)
+			return obtained.(uint64) == expected, expected
+		},
+	)
+}
+
 var parseStringTest = []ParseTest{
 	{
 		in:     `\uFF11`,
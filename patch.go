@@ -0,0 +1,311 @@
+package jsonparser
+
+import (
+	"bytes"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ApplyPatch applies a JSON Patch (RFC 6902) to doc and returns the patched document.
+// Operations are applied in order, and the patch is atomic: on any error doc is left untouched
+// and no partial result is returned. Failed test operations return PatchTestFailedError.
+func ApplyPatch(doc, patch []byte) ([]byte, error) {
+	start := nextToken(patch)
+	if start == -1 || patch[start] != '[' {
+		return nil, InvalidPatchError
+	}
+	ops, err := containerSpans(patch, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set and Delete never modify their input, so doc stays untouched whatever happens
+	out := doc
+	for _, op := range ops {
+		if out, err = applyPatchOp(out, patch[op.offset:op.endOffset]); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// applyPatchOp applies a single JSON Patch operation.
+func applyPatchOp(doc, op []byte) ([]byte, error) {
+	if _, dt, _, err := Get(op); err != nil || dt != Object {
+		return nil, InvalidPatchError
+	}
+
+	name, err := GetString(op, "op")
+	if err != nil {
+		return nil, InvalidPatchError
+	}
+	path, err := GetString(op, "path")
+	if err != nil {
+		return nil, InvalidPatchError
+	}
+
+	switch name {
+	case "add", "replace", "test":
+		value, err := rawValue(op, "value")
+		if err == KeyPathNotFoundError {
+			return nil, InvalidPatchError
+		} else if err != nil {
+			return nil, err
+		}
+
+		if name == "add" {
+			return patchAdd(doc, path, value)
+		}
+
+		keys, err := pointerKeys(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		current, err := rawValue(doc, keys...)
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "test" {
+			if !equalValues(current, value) {
+				return nil, PatchTestFailedError
+			}
+			return doc, nil
+		} else if len(keys) == 0 {
+			return value, nil
+		}
+		return Set(doc, value, keys...)
+	case "remove":
+		return patchRemove(doc, path)
+	case "move", "copy":
+		from, err := GetString(op, "from")
+		if err != nil {
+			return nil, InvalidPatchError
+		}
+		keys, err := pointerKeys(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		value, err := rawValue(doc, keys...)
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "move" {
+			if from == path {
+				return doc, nil
+			}
+			// A value cannot be moved into one of its own children
+			if strings.HasPrefix(path, from+"/") {
+				return nil, InvalidPatchError
+			}
+			if doc, err = patchRemove(doc, from); err != nil {
+				return nil, err
+			}
+		}
+		return patchAdd(doc, path, value)
+	}
+
+	return nil, InvalidPatchError
+}
+
+// patchAdd adds value at the JSON Pointer path. Array elements are inserted rather than replaced,
+// and "-" appends to an array.
+func patchAdd(doc []byte, path string, value []byte) ([]byte, error) {
+	keys, err := pointerKeys(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return value, nil
+	}
+
+	if isArray, _ := isValidArrayIndex(keys[len(keys)-1]); isArray {
+		return Insert(doc, value, keys...)
+	}
+
+	return Set(doc, value, keys...)
+}
+
+// patchRemove removes the value at the JSON Pointer path, which must exist.
+func patchRemove(doc []byte, path string) ([]byte, error) {
+	keys, err := pointerKeys(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, InvalidPatchError
+	}
+	if _, _, _, err := Get(doc, keys...); err != nil {
+		return nil, err
+	}
+
+	return Delete(doc, keys...), nil
+}
+
+// pointerKeys converts a JSON Pointer (RFC 6901) to keys for the other functions of the package.
+// Whether a reference token names an object member or an array element depends on the document,
+// so every token but the last must resolve to an existing object or array. The "-" token of
+// arrays becomes Append().
+func pointerKeys(doc []byte, pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, InvalidPatchError
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	keys := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		// "~1" stands for '/' and "~0" for '~', and must be decoded in that order
+		for i := 0; i < len(token); i++ {
+			if token[i] == '~' && (i+1 == len(token) || (token[i+1] != '0' && token[i+1] != '1')) {
+				return nil, InvalidPatchError
+			}
+		}
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+
+		_, dataType, _, err := Get(doc, keys...)
+		if err != nil {
+			return nil, err
+		}
+
+		switch dataType {
+		case Object:
+			keys = append(keys, Key(token))
+		case Array:
+			if token == "-" {
+				keys = append(keys, Append())
+			} else if idx, err := strconv.Atoi(token); err != nil || idx < 0 || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+				return nil, KeyPathNotFoundError
+			} else {
+				keys = append(keys, Index(idx))
+			}
+		default:
+			return nil, KeyPathNotFoundError
+		}
+	}
+
+	return keys, nil
+}
+
+// rawValue returns a copy of the value at keys as it appears in data, with the quotes of strings.
+func rawValue(data []byte, keys ...string) ([]byte, error) {
+	_, _, offset, endOffset, err := internalGet(data, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), data[offset:endOffset]...), nil
+}
+
+// equalValues reports whether two JSON values are equal as RFC 6902 defines it for test operations:
+// numbers are compared by value, strings after unescaping, and objects regardless of member order.
+func equalValues(a, b []byte) bool {
+	av, at, _, aErr := Get(a)
+	bv, bt, _, bErr := Get(b)
+	if aErr != nil || bErr != nil || at != bt {
+		return false
+	}
+
+	switch at {
+	case String:
+		au, aErr := Unescape(av, nil)
+		bu, bErr := Unescape(bv, nil)
+		return aErr == nil && bErr == nil && bytes.Equal(au, bu)
+	case Number:
+		return equalNumbers(av, bv)
+	case Object:
+		aMembers, aOk := objectMembers(av)
+		bMembers, bOk := objectMembers(bv)
+		if !aOk || !bOk || len(aMembers) != len(bMembers) {
+			return false
+		}
+		for k, v := range aMembers {
+			if w, ok := bMembers[k]; !ok || !equalValues(v, w) {
+				return false
+			}
+		}
+		return true
+	case Array:
+		aSpans, aErr := containerSpans(av, 0)
+		bSpans, bErr := containerSpans(bv, 0)
+		if aErr != nil || bErr != nil || len(aSpans) != len(bSpans) {
+			return false
+		}
+		for i := range aSpans {
+			if !equalValues(av[aSpans[i].offset:aSpans[i].endOffset], bv[bSpans[i].offset:bSpans[i].endOffset]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// true, false and null
+	return bytes.Equal(av, bv)
+}
+
+// equalNumbers reports whether two JSON numbers have the same value. Both are reduced to their significant
+// digits and a power of ten, so exponents such as the one in 1e999999 are compared rather than expanded.
+func equalNumbers(a, b []byte) bool {
+	aNeg, aDigits, aExp, aOk := splitNumber(a)
+	bNeg, bDigits, bExp, bOk := splitNumber(b)
+	if !aOk || !bOk {
+		return false
+	}
+	if len(aDigits) == 0 || len(bDigits) == 0 {
+		// Zero, whatever its sign and exponent
+		return len(aDigits) == len(bDigits)
+	}
+	return aNeg == bNeg && bytes.Equal(aDigits, bDigits) && aExp.Cmp(bExp) == 0
+}
+
+// splitNumber writes the valid JSON number b as sign, digits and exponent, where digits has no leading or
+// trailing zeros and the number is digits times 10^exp. digits is empty when the number is zero.
+func splitNumber(b []byte) (neg bool, digits []byte, exp *big.Int, ok bool) {
+	if end, err := validateNumber(b, 0); err != nil || end != len(b) {
+		return false, nil, nil, false
+	}
+
+	if neg = b[0] == '-'; neg {
+		b = b[1:]
+	}
+
+	// The exponent is kept as a big.Int, as its digits alone may not fit an int64
+	exp = new(big.Int)
+	if i := bytes.IndexAny(b, "eE"); i != -1 {
+		exp.SetString(string(b[i+1:]), 10)
+		b = b[:i]
+	}
+
+	digits = make([]byte, 0, len(b))
+	fracLen := 0
+	if i := bytes.IndexByte(b, '.'); i != -1 {
+		digits = append(append(digits, b[:i]...), b[i+1:]...)
+		fracLen = len(b) - i - 1
+	} else {
+		digits = append(digits, b...)
+	}
+
+	digits = bytes.TrimLeft(digits, "0")
+	trimmed := bytes.TrimRight(digits, "0")
+	exp.Add(exp, big.NewInt(int64(len(digits)-len(trimmed)-fracLen)))
+	return neg, trimmed, exp, true
+}
+
+// objectMembers maps the keys of an object to their values. Repeated keys are read like encoding/json
+// reads them, the last one winning.
+func objectMembers(data []byte) (map[string][]byte, bool) {
+	spans, err := containerSpans(data, 0)
+	if err != nil {
+		return nil, false
+	}
+
+	members := make(map[string][]byte, len(spans))
+	for _, s := range spans {
+		members[string(s.key)] = data[s.offset:s.endOffset]
+	}
+	return members, true
+}
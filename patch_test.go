@@ -0,0 +1,243 @@
+package jsonparser
+
+import (
+	"testing"
+)
+
+// Cases follow the examples of RFC 6902, Appendix A
+var applyPatchTests = []struct {
+	desc     string
+	doc      string
+	patch    string
+	expected string
+	err      error
+}{
+	{
+		desc:     "adding an object member",
+		doc:      `{"foo":"bar"}`,
+		patch:    `[{"op":"add","path":"/baz","value":"qux"}]`,
+		expected: `{"foo":"bar","baz":"qux"}`,
+	},
+	{
+		desc:     "adding an array element",
+		doc:      `{"foo":["bar","baz"]}`,
+		patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+		expected: `{"foo":["bar","qux","baz"]}`,
+	},
+	{
+		desc:     "adding an array element at the end",
+		doc:      `{"foo":["bar"]}`,
+		patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+		expected: `{"foo":["bar","qux"]}`,
+	},
+	{
+		desc:     "appending to an empty array",
+		doc:      `{"foo":[]}`,
+		patch:    `[{"op":"add","path":"/foo/-","value":1}]`,
+		expected: `{"foo":[1]}`,
+	},
+	{
+		desc:     "removing an object member",
+		doc:      `{"baz":"qux","foo":"bar"}`,
+		patch:    `[{"op":"remove","path":"/baz"}]`,
+		expected: `{"foo":"bar"}`,
+	},
+	{
+		desc:     "removing an array element",
+		doc:      `{"foo":["bar","qux","baz"]}`,
+		patch:    `[{"op":"remove","path":"/foo/1"}]`,
+		expected: `{"foo":["bar","baz"]}`,
+	},
+	{
+		desc:     "replacing a value",
+		doc:      `{"baz":"qux","foo":"bar"}`,
+		patch:    `[{"op":"replace","path":"/baz","value":"boo"}]`,
+		expected: `{"baz":"boo","foo":"bar"}`,
+	},
+	{
+		desc:     "moving a value",
+		doc:      `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+		patch:    `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+		expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+	},
+	{
+		desc:     "moving an array element",
+		doc:      `{"foo":["all","grass","cows","eat"]}`,
+		patch:    `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+		expected: `{"foo":["all","cows","eat","grass"]}`,
+	},
+	{
+		desc:     "copying a value",
+		doc:      `{"foo":{"bar":1}}`,
+		patch:    `[{"op":"copy","from":"/foo","path":"/baz"}]`,
+		expected: `{"foo":{"bar":1},"baz":{"bar":1}}`,
+	},
+	{
+		desc:     "testing a value: success",
+		doc:      `{"baz":"qux","foo":["a",2,"c"]}`,
+		patch:    `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+		expected: `{"baz":"qux","foo":["a",2,"c"]}`,
+	},
+	{
+		desc:  "testing a value: error",
+		doc:   `{"baz":"qux"}`,
+		patch: `[{"op":"test","path":"/baz","value":"bar"}]`,
+		err:   PatchTestFailedError,
+	},
+	{
+		desc:     "adding a nested member object",
+		doc:      `{"foo":"bar"}`,
+		patch:    `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+		expected: `{"foo":"bar","child":{"grandchild":{}}}`,
+	},
+	{
+		desc:  "adding to a nonexistent target",
+		doc:   `{"foo":"bar"}`,
+		patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+		err:   KeyPathNotFoundError,
+	},
+	{
+		desc:     "escape ordering",
+		doc:      `{"/":9,"~1":10}`,
+		patch:    `[{"op":"test","path":"/~01","value":10}]`,
+		expected: `{"/":9,"~1":10}`,
+	},
+	{
+		desc:  "comparing strings and numbers",
+		doc:   `{"/":9,"~1":10}`,
+		patch: `[{"op":"test","path":"/~01","value":"10"}]`,
+		err:   PatchTestFailedError,
+	},
+	{
+		desc:     "adding an array value",
+		doc:      `{"foo":["bar"]}`,
+		patch:    `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+		expected: `{"foo":["bar",["abc","def"]]}`,
+	},
+	{
+		desc:     "appending to a top level array",
+		doc:      `[1, 2 ]`,
+		patch:    `[{"op":"add","path":"/-","value":3},{"op":"add","path":"/0","value":0}]`,
+		expected: `[0,1, 2,3 ]`,
+	},
+	{
+		desc:     "keys that look like indexes",
+		doc:      `{"[0]":1}`,
+		patch:    `[{"op":"replace","path":"/[0]","value":2}]`,
+		expected: `{"[0]":2}`,
+	},
+	{
+		desc:     "numeric keys of objects",
+		doc:      `{"0":1}`,
+		patch:    `[{"op":"remove","path":"/0"}]`,
+		expected: `{}`,
+	},
+	{
+		desc:     "replacing the whole document",
+		doc:      `{"a":1}`,
+		patch:    `[{"op":"replace","path":"","value":[1]}]`,
+		expected: `[1]`,
+	},
+	{
+		desc:     "equality ignores member order and number formatting",
+		doc:      `{"a":{"x":1,"y":[1.0,"\u0041"]}}`,
+		patch:    `[{"op":"test","path":"/a","value":{"y":[1e0,"A"],"x":10e-1}}]`,
+		expected: `{"a":{"x":1,"y":[1.0,"\u0041"]}}`,
+	},
+	{
+		desc:  "failed operation leaves the document unchanged",
+		doc:   `{"a":[1,2]}`,
+		patch: `[{"op":"remove","path":"/a/0"},{"op":"remove","path":"/b"}]`,
+		err:   KeyPathNotFoundError,
+	},
+	{
+		desc:  "array index out of range",
+		doc:   `{"a":[1]}`,
+		patch: `[{"op":"add","path":"/a/3","value":2}]`,
+		err:   KeyPathNotFoundError,
+	},
+	{
+		desc:  "leading zeros in array indexes",
+		doc:   `{"a":[1,2]}`,
+		patch: `[{"op":"remove","path":"/a/01"}]`,
+		err:   KeyPathNotFoundError,
+	},
+	{
+		desc:  "moving a value into its own child",
+		doc:   `{"a":{"b":{}}}`,
+		patch: `[{"op":"move","from":"/a","path":"/a/b/c"}]`,
+		err:   InvalidPatchError,
+	},
+	{
+		desc:  "missing value",
+		doc:   `{}`,
+		patch: `[{"op":"add","path":"/a"}]`,
+		err:   InvalidPatchError,
+	},
+	{
+		desc:  "unknown operation",
+		doc:   `{}`,
+		patch: `[{"op":"merge","path":"/a","value":1}]`,
+		err:   InvalidPatchError,
+	},
+	{
+		desc:  "invalid escape in pointer",
+		doc:   `{}`,
+		patch: `[{"op":"add","path":"/a~2","value":1}]`,
+		err:   InvalidPatchError,
+	},
+	{
+		desc:  "patch is not an array",
+		doc:   `{}`,
+		patch: `{"op":"add","path":"/a","value":1}`,
+		err:   InvalidPatchError,
+	},
+}
+
+func TestApplyPatch(t *testing.T) {
+	for _, test := range applyPatchTests {
+		doc, patch := []byte(test.doc), []byte(test.patch)
+		value, err := ApplyPatch(doc, patch)
+
+		if err != test.err {
+			t.Errorf("ApplyPatch test '%s' expected error %v, obtained %v", test.desc, test.err, err)
+		} else if err == nil && string(value) != test.expected {
+			t.Errorf("ApplyPatch test '%s' expected %s, obtained %s", test.desc, test.expected, value)
+		}
+		if string(doc) != test.doc || string(patch) != test.patch {
+			t.Errorf("ApplyPatch test '%s' modified its input: %s, %s", test.desc, doc, patch)
+		}
+	}
+}
+
+func TestEqualNumbers(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{"1", "1.0", true},
+		{"100", "1e2", true},
+		{"1.50", "15E-1", true},
+		{"0.001", "1e-3", true},
+		{"-0", "0.0e5", true},
+		{"0", "0e-99999999999999999999", true},
+		{"-12", "-1.2e+1", true},
+		{"1e999999", "10e999998", true},
+		{"1e99999999999999999999", "0.1e100000000000000000000", true},
+		{"1", "-1", false},
+		{"1", "2", false},
+		{"10", "1", false},
+		{"1e999999", "1e999998", false},
+		{"1e99999999999999999999", "1e99999999999999999998", false},
+		{"1", "1.", false},
+		{"01", "1", false},
+	}
+	for _, test := range tests {
+		if equal := equalNumbers([]byte(test.a), []byte(test.b)); equal != test.equal {
+			t.Errorf("equalNumbers(%s, %s) returned %t", test.a, test.b, equal)
+		}
+		if equal := equalNumbers([]byte(test.b), []byte(test.a)); equal != test.equal {
+			t.Errorf("equalNumbers(%s, %s) returned %t", test.b, test.a, equal)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package jsonparser
+
+import "strconv"
+
+// Paths are lists of strings, one per level: a plain string names an object key, and a bracketed
+// string such as "[3]" addresses an array element. The functions below build segments explicitly,
+// so that keys which look like indexes can still be addressed. Their results are ordinary strings
+// and can be passed to any function taking keys.
+//
+// Segments starting with a NUL byte followed by '[' or another NUL byte are reserved for Key: the
+// leading NUL is dropped before matching. Any other segment, including ones starting with a single
+// NUL, names the key it spells out.
+
+// keyMarker prefixes segments made by Key whose names would otherwise be read as array indexes.
+const keyMarker = '\x00'
+
+// Key returns a path segment naming the object member name, even when name starts with '['.
+func Key(name string) string {
+	if len(name) > 0 && (name[0] == '[' || name[0] == keyMarker) {
+		return string(keyMarker) + name
+	}
+	return name
+}
+
+// Index returns a path segment addressing the i'th element of an array. It panics if i is negative.
+func Index(i int) string {
+	if i < 0 {
+		panic("jsonparser: negative array index")
+	}
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// Append returns a path segment that makes Set add a new element at the end of an array.
+func Append() string {
+	return "[+]"
+}
+
+// Prepend returns a path segment that makes Set add a new element at the start of an array.
+func Prepend() string {
+	return "[-]"
+}
+
+// segmentName returns the object key named by a path segment, without the marker added by Key.
+func segmentName(seg string) string {
+	if len(seg) > 1 && seg[0] == keyMarker && (seg[1] == '[' || seg[1] == keyMarker) {
+		return seg[1:]
+	}
+	return seg
+}
@@ -0,0 +1,139 @@
+package jsonparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathSegments(t *testing.T) {
+	segmentTests := []struct {
+		seg      string
+		expected string
+	}{
+		{Key("a"), "a"},
+		{Key(""), ""},
+		{Key("[0]"), "\x00[0]"},
+		{Key("\x00a"), "\x00\x00a"},
+		{Index(0), "[0]"},
+		{Index(12), "[12]"},
+		{Append(), "[+]"},
+		{Prepend(), "[-]"},
+	}
+	for _, test := range segmentTests {
+		if test.seg != test.expected {
+			t.Errorf("Expected segment %q, obtained %q", test.expected, test.seg)
+		}
+	}
+
+	for _, name := range []string{"a", "[0]", "\x00a", "[+]"} {
+		if n := segmentName(Key(name)); n != name {
+			t.Errorf("segmentName(Key(%q)) returned %q", name, n)
+		}
+	}
+}
+
+func TestIndexNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Index(-1) did not panic")
+		}
+	}()
+	Index(-1)
+}
+
+func TestGetKeyStartingWithNul(t *testing.T) {
+	data := []byte(`{"\u0000a":1,"a":2,"\u0000[0]":3}`)
+	getTests := []struct {
+		path     []string
+		expected string
+	}{
+		{[]string{"\x00a"}, "1"},
+		{[]string{Key("\x00a")}, "1"},
+		{[]string{"a"}, "2"},
+		{[]string{Key("\x00[0]")}, "3"},
+	}
+	for _, test := range getTests {
+		if v, _, _, err := Get(data, test.path...); err != nil || string(v) != test.expected {
+			t.Errorf("Get(%q) returned %s, %v; expected %s", test.path, v, err, test.expected)
+		}
+	}
+
+	if v, err := Set(data, []byte(`4`), "\x00a"); err != nil || string(v) != `{"\u0000a":4,"a":2,"\u0000[0]":3}` {
+		t.Errorf("Set returned %s, %v", v, err)
+	}
+}
+
+var keyLikeIndexDoc = []byte(`{"[0]":{"[1]":"x"},"a":[1,{"[x":2}],"\u0000b":3}`)
+
+func TestGetKeyLikeIndex(t *testing.T) {
+	getTests := []struct {
+		path     []string
+		expected string
+	}{
+		{[]string{Key("[0]"), Key("[1]")}, "x"},
+		{[]string{"a", Index(1), Key("[x")}, "2"},
+		{[]string{Key("\x00b")}, "3"},
+	}
+	for _, test := range getTests {
+		if v, _, _, err := Get(keyLikeIndexDoc, test.path...); err != nil || string(v) != test.expected {
+			t.Errorf("Get(%q) returned %s, %v; expected %s", test.path, v, err, test.expected)
+		}
+		if v, _, _, err := (&Parser{DuplicateKeys: LastKeyWins}).Get(keyLikeIndexDoc, test.path...); err != nil || string(v) != test.expected {
+			t.Errorf("Parser.Get(%q) returned %s, %v; expected %s", test.path, v, err, test.expected)
+		}
+	}
+
+	var found []string
+	EachKey(keyLikeIndexDoc, func(idx int, value []byte, vt ValueType, err error) {
+		found = append(found, string(value))
+	}, []string{Key("[0]"), Key("[1]")}, []string{Key("\x00b")})
+	if !reflect.DeepEqual(found, []string{"x", "3"}) {
+		t.Errorf("EachKey returned %q", found)
+	}
+
+	var keys []string
+	ObjectEach(keyLikeIndexDoc, func(key []byte, value []byte, dataType ValueType, offset int) error {
+		keys = append(keys, string(key))
+		return nil
+	}, Key("[0]"))
+	if !reflect.DeepEqual(keys, []string{"[1]"}) {
+		t.Errorf("ObjectEach returned keys %q", keys)
+	}
+}
+
+func TestSetDeleteKeyLikeIndex(t *testing.T) {
+	setTests := []struct {
+		data     string
+		path     []string
+		expected string
+	}{
+		{`{"[0]":1}`, []string{Key("[0]")}, `{"[0]":2}`},
+		{`{}`, []string{Key("[0]")}, `{"[0]":2}`},
+		{`{"a":{}}`, []string{"a", Key("[0]"), Index(0)}, `{"a":{"[0]":[2]}}`},
+		{`{"a":[1]}`, []string{"a", Append()}, `{"a":[1,2]}`},
+		{`{"a":[1]}`, []string{"a", Prepend()}, `{"a":[2,1]}`},
+	}
+	for _, test := range setTests {
+		if v, err := Set([]byte(test.data), []byte(`2`), test.path...); err != nil || string(v) != test.expected {
+			t.Errorf("Set(%s, %q) returned %s, %v; expected %s", test.data, test.path, v, err, test.expected)
+		}
+	}
+
+	deleteTests := []struct {
+		data     string
+		path     []string
+		expected string
+	}{
+		{`{"[0]":1,"b":2}`, []string{Key("[0]")}, `{"b":2}`},
+		{`{"a":{"b":1,"[0]":[1]}}`, []string{"a", Key("[0]")}, `{"a":{"b":1}}`},
+		{`{"a":{"[0]":[1,2]}}`, []string{"a", Key("[0]"), Index(1)}, `{"a":{"[0]":[1]}}`},
+	}
+	for _, test := range deleteTests {
+		if v := Delete([]byte(test.data), test.path...); string(v) != test.expected {
+			t.Errorf("Delete(%s, %q) returned %s; expected %s", test.data, test.path, v, test.expected)
+		}
+		if v := (&Parser{DuplicateKeys: LastKeyWins}).Delete([]byte(test.data), test.path...); string(v) != test.expected {
+			t.Errorf("Parser.Delete(%s, %q) returned %s; expected %s", test.data, test.path, v, test.expected)
+		}
+	}
+}
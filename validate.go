@@ -0,0 +1,253 @@
+package jsonparser
+
+// How many levels of nesting validate can track without allocating
+const validateStackBufSize = 32
+
+// validate checks that data holds exactly one JSON value as defined by RFC 7159, optionally surrounded by whitespace.
+// Unlike the rest of the package it reads every byte, so it catches the malformed input the other functions tolerate.
+func validate(data []byte) error {
+	_, err := walkValue(nil, data, false)
+	return err
+}
+
+// walkValue is validate, also appending data without insignificant whitespace to dst when compact is set.
+// Every token is appended once it has been read, so dst may share memory with data[:0].
+func walkValue(dst, data []byte, compact bool) ([]byte, error) {
+	var stackbuf [validateStackBufSize]byte // stack-allocated array for allocation-free validation of shallow documents
+	stack := stackbuf[:0]
+	i := skipWhitespace(data, 0)
+
+	for {
+		// A value is expected at data[i]
+		if i >= len(data) {
+			return nil, MalformedJsonError
+		}
+
+		start := i
+		var err error
+		switch data[i] {
+		case '{':
+			stack = append(stack, '{')
+			if compact {
+				dst = append(dst, '{')
+			}
+			if i = skipWhitespace(data, i+1); i < len(data) && data[i] == '}' {
+				stack = stack[:len(stack)-1]
+				i++
+			} else if dst, i, err = walkKey(dst, data, i, compact); err != nil {
+				return nil, err
+			} else {
+				continue
+			}
+		case '[':
+			stack = append(stack, '[')
+			if compact {
+				dst = append(dst, '[')
+			}
+			if i = skipWhitespace(data, i+1); i < len(data) && data[i] == ']' {
+				stack = stack[:len(stack)-1]
+				i++
+			} else {
+				continue
+			}
+		case '"':
+			if i, err = validateString(data, i); err != nil {
+				return nil, err
+			}
+		case 't':
+			if i, err = validateLiteral(data, i, trueLiteral); err != nil {
+				return nil, err
+			}
+		case 'f':
+			if i, err = validateLiteral(data, i, falseLiteral); err != nil {
+				return nil, err
+			}
+		case 'n':
+			if i, err = validateLiteral(data, i, nullLiteral); err != nil {
+				return nil, err
+			}
+		default:
+			if i, err = validateNumber(data, i); err != nil {
+				return nil, err
+			}
+		}
+		if compact {
+			// Scalars are copied as they are, and empty objects and arrays lose the whitespace inside them
+			if data[start] == '{' || data[start] == '[' {
+				dst = append(dst, data[i-1])
+			} else {
+				dst = append(dst, data[start:i]...)
+			}
+		}
+
+		// A value ended just before data[i]: close containers until another value is expected
+		for expectValue := false; !expectValue; {
+			i = skipWhitespace(data, i)
+			if len(stack) == 0 {
+				if i != len(data) {
+					return nil, MalformedJsonError
+				}
+				return dst, nil
+			}
+
+			top := stack[len(stack)-1]
+			if i >= len(data) {
+				if top == '{' {
+					return nil, MalformedObjectError
+				}
+				return nil, MalformedArrayError
+			}
+
+			switch {
+			case data[i] == ',' && top == '{':
+				if compact {
+					dst = append(dst, ',')
+				}
+				if dst, i, err = walkKey(dst, data, skipWhitespace(data, i+1), compact); err != nil {
+					return nil, err
+				}
+				expectValue = true
+			case data[i] == ',':
+				if compact {
+					dst = append(dst, ',')
+				}
+				i = skipWhitespace(data, i+1)
+				expectValue = true
+			case data[i] == '}' && top == '{', data[i] == ']' && top == '[':
+				if compact {
+					dst = append(dst, data[i])
+				}
+				stack = stack[:len(stack)-1]
+				i++
+			case top == '{':
+				return nil, MalformedObjectError
+			default:
+				return nil, MalformedArrayError
+			}
+		}
+	}
+}
+
+func skipWhitespace(data []byte, i int) int {
+	if off := nextToken(data[i:]); off != -1 {
+		return i + off
+	}
+	return len(data)
+}
+
+// walkKey checks an object key and its colon starting at data[i], appending them to dst when compact is set,
+// and returns the offset of the value.
+func walkKey(dst, data []byte, i int, compact bool) ([]byte, int, error) {
+	if i >= len(data) || data[i] != '"' {
+		return dst, i, MalformedObjectError
+	}
+
+	end, err := validateString(data, i)
+	if err != nil {
+		return dst, end, err
+	}
+	if compact {
+		dst = append(dst, data[i:end]...)
+	}
+
+	if i = skipWhitespace(data, end); i >= len(data) || data[i] != ':' {
+		return dst, i, MalformedObjectError
+	}
+	if compact {
+		dst = append(dst, ':')
+	}
+
+	return dst, skipWhitespace(data, i+1), nil
+}
+
+// validateString checks the string starting at data[i], and returns the offset following its closing quote.
+func validateString(data []byte, i int) (int, error) {
+	for i++; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '"':
+			return i + 1, nil
+		case c < 0x20:
+			return i, MalformedStringError
+		case c == '\\':
+			if i+1 >= len(data) {
+				return i, MalformedStringEscapeError
+			}
+			switch data[i+1] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				i++
+			case 'u':
+				if i+5 >= len(data) {
+					return i, MalformedStringEscapeError
+				}
+				for _, h := range data[i+2 : i+6] {
+					if h2I(h) == badHex {
+						return i, MalformedStringEscapeError
+					}
+				}
+				i += 5
+			default:
+				return i, MalformedStringEscapeError
+			}
+		}
+	}
+
+	return i, MalformedStringError
+}
+
+// validateLiteral checks that literal starts at data[i], and returns the offset following it.
+func validateLiteral(data []byte, i int, literal []byte) (int, error) {
+	if len(data)-i < len(literal) || string(data[i:i+len(literal)]) != string(literal) {
+		return i, MalformedValueError
+	}
+
+	return i + len(literal), nil
+}
+
+// validateNumber checks the number starting at data[i], and returns the offset following it.
+func validateNumber(data []byte, i int) (int, error) {
+	if i < len(data) && data[i] == '-' {
+		i++
+	}
+
+	// Integer part: a single zero, or digits not starting with zero
+	if i < len(data) && data[i] == '0' {
+		i++
+	} else if digits := countDigits(data[i:]); digits == 0 {
+		return i, MalformedValueError
+	} else {
+		i += digits
+	}
+
+	// Fraction
+	if i < len(data) && data[i] == '.' {
+		digits := countDigits(data[i+1:])
+		if digits == 0 {
+			return i, MalformedValueError
+		}
+		i += 1 + digits
+	}
+
+	// Exponent
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		i++
+		if i < len(data) && (data[i] == '+' || data[i] == '-') {
+			i++
+		}
+		digits := countDigits(data[i:])
+		if digits == 0 {
+			return i, MalformedValueError
+		}
+		i += digits
+	}
+
+	return i, nil
+}
+
+func countDigits(data []byte) int {
+	for i, c := range data {
+		if c < '0' || c > '9' {
+			return i
+		}
+	}
+	return len(data)
+}
@@ -0,0 +1,72 @@
+package jsonparser
+
+import (
+	"strings"
+	"testing"
+)
+
+type ValidateTest struct {
+	desc string
+	json string
+
+	isErr bool
+}
+
+var validateTests = []ValidateTest{
+	// Valid documents
+	{desc: "string", json: `"abc"`},
+	{desc: "empty string", json: `""`},
+	{desc: "escapes", json: `"\"\\\/\b\f\n\r\té😃"`},
+	{desc: "zero", json: `0`},
+	{desc: "negative fraction with exponent", json: `-0.5e-10`},
+	{desc: "exponent with plus", json: `12E+3`},
+	{desc: "literals", json: `[true,false,null]`},
+	{desc: "empty object", json: `{}`},
+	{desc: "empty array", json: `[ ]`},
+	{desc: "nested", json: `{"a":[1,{"b":{}},[]],"c":"d"}`},
+	{desc: "surrounding whitespace", json: " \n\t{ \"a\" : [ 1 , 2 ] }\r\n "},
+	{desc: "deep nesting", json: strings.Repeat("[", 100) + strings.Repeat("]", 100)},
+
+	// Invalid documents
+	{desc: "empty", json: ``, isErr: true},
+	{desc: "whitespace only", json: `  `, isErr: true},
+	{desc: "two values", json: `1 2`, isErr: true},
+	{desc: "trailing garbage", json: `{"a":1}x`, isErr: true},
+	{desc: "unclosed object", json: `{"a":1 `, isErr: true},
+	{desc: "unclosed array", json: `[1,2`, isErr: true},
+	{desc: "mismatched brackets", json: `[1}`, isErr: true},
+	{desc: "trailing comma in object", json: `{"a":1,}`, isErr: true},
+	{desc: "trailing comma in array", json: `[1,]`, isErr: true},
+	{desc: "missing comma", json: `{"a":1 "b":2}`, isErr: true},
+	{desc: "missing colon", json: `{"a" 1}`, isErr: true},
+	{desc: "colon chain", json: `{"a":"b":"c"}`, isErr: true},
+	{desc: "unquoted key", json: `{a:1}`, isErr: true},
+	{desc: "number key", json: `{1:1}`, isErr: true},
+	{desc: "unterminated string", json: `"abc`, isErr: true},
+	{desc: "control character in string", json: "\"a\tb\"", isErr: true},
+	{desc: "invalid escape", json: `"\x"`, isErr: true},
+	{desc: "short unicode escape", json: `"\u12"`, isErr: true},
+	{desc: "bad hex in unicode escape", json: `"\u12G4"`, isErr: true},
+	{desc: "leading zero", json: `01`, isErr: true},
+	{desc: "leading plus", json: `+1`, isErr: true},
+	{desc: "bare minus", json: `-`, isErr: true},
+	{desc: "missing fraction digits", json: `1.`, isErr: true},
+	{desc: "missing exponent digits", json: `1e+`, isErr: true},
+	{desc: "hex number", json: `0x10`, isErr: true},
+	{desc: "truncated literal", json: `tru`, isErr: true},
+	{desc: "unknown literal", json: `undefined`, isErr: true},
+	{desc: "literal prefix", json: `nulls`, isErr: true},
+}
+
+func TestValidate(t *testing.T) {
+	for _, test := range validateTests {
+		if activeTest != "" && test.desc != activeTest {
+			continue
+		}
+
+		err := validate([]byte(test.json))
+		if isErr := (err != nil); test.isErr != isErr {
+			t.Errorf("validate test '%s' isErr mismatch: expected %t, obtained %t (err %v)", test.desc, test.isErr, isErr, err)
+		}
+	}
+}